@@ -27,5 +27,8 @@ func NewAuthCmd() *cobra.Command {
 		Long:                  `Authenticate with Monoskope instance, check status and more.`,
 	}
 	cmd.AddCommand(NewAuthStatusCmd())
+	cmd.AddCommand(NewAuthWhoAmICmd())
+	cmd.AddCommand(NewAuthRenewCmd())
+	cmd.AddCommand(NewAuthClustersCmd())
 	return cmd
 }
@@ -0,0 +1,39 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	"github.com/spf13/cobra"
+)
+
+func NewAuthClustersListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List cached cluster tokens",
+		Long:  `Lists the cluster tokens cached by monoctl and whether they are still valid.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			if err := configManager.LoadConfig(); err != nil {
+				return fmt.Errorf("failed loading monoconfig: %w", err)
+			}
+			return usecases.NewGetClusterAuthUseCase(configManager.GetConfig()).Run(cmd.Context())
+		},
+	}
+}
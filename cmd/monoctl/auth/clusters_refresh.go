@@ -0,0 +1,47 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
+	"github.com/spf13/cobra"
+)
+
+func NewAuthClustersRefreshCmd() *cobra.Command {
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Fetch tokens for all accessible clusters",
+		Long: `Fetches tokens for every cluster and role you have access to and caches them, so that kubectl does not have to wait for monoctl later on.
+Tokens are fetched in parallel, failures for single clusters are reported without aborting the others.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+				return usecases.NewRefreshClusterAuthUseCase(configManager, concurrency).Run(ctx)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&concurrency, "concurrency", usecases.DefaultClusterAuthConcurrency, "Number of tokens fetched in parallel")
+
+	return cmd
+}
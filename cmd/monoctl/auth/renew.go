@@ -0,0 +1,43 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
+	"github.com/spf13/cobra"
+)
+
+func NewAuthRenewCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "renew",
+		Short: "Renew the session",
+		Long: `Renews the session with the Monoskope instance ahead of time, even if the current one is still valid.
+Monoskope offers no way to renew a session without the user, so this opens the browser to log in like auth does.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+
+			if err := auth_util.Renew(cmd.Context(), configManager); err != nil {
+				return err
+			}
+
+			fmt.Printf("Token expiry: %v\n", configManager.GetConfig().AuthInformation.Expiry)
+			return nil
+		},
+	}
+}
@@ -41,7 +41,7 @@ func NewAuthStatusCmd() *cobra.Command {
 			if authenticated {
 				fmt.Printf("Server: %v\n", conf.Server)
 				fmt.Printf("Token expiry: %v\n", conf.AuthInformation.Expiry)
-				fmt.Printf("Token expired: %v\n", conf.AuthInformation.IsTokenExpired())
+				fmt.Printf("Token expired: %v\n", !conf.IsAuthInformationValid())
 			}
 
 			return nil
@@ -0,0 +1,52 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/output"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
+	"github.com/spf13/cobra"
+)
+
+func NewAuthWhoAmICmd() *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Show the authenticated user and their access",
+		Long:  `Shows the authenticated user, their rolebindings, the tenants they belong to and the clusters and roles they can access.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := output.ParseOutputFormat(outputFormat)
+			if err != nil {
+				return err
+			}
+
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+				return usecases.NewWhoAmIUseCase(configManager.GetConfig(), format).Run(ctx)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&outputFormat, "output", "o", string(output.TableFormat), "Output format. One of: table, json")
+
+	return cmd
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
+	"github.com/spf13/cobra"
+)
+
+func NewCheckClusterCmd() *cobra.Command {
+	var role string
+	accessCheck := &usecases.ClusterAccessCheck{}
+
+	cmd := &cobra.Command{
+		Use:   "cluster <NAME>",
+		Short: "Check connectivity and RBAC of a cluster.",
+		Long: `Checks a cluster can be used with the credentials issued by Monoskope.
+TLS against the CA bundle known to Monoskope, authentication of the cluster token and authorization via SelfSubjectAccessReview are reported separately.
+Without --role every role you have on the cluster is checked.`,
+		Example: `  monoctl check cluster my-cluster
+  monoctl check cluster my-cluster --role admin --verb list --resource deployments --namespace kube-system`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+				return usecases.NewCheckClusterUseCase(configManager, args[0], role, accessCheck).Run(ctx)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&role, "role", "r", "", "Role to check, all of your roles on the cluster if not given.")
+	flags.StringVar(&accessCheck.Verb, "verb", "get", "Verb checked by the SelfSubjectAccessReview.")
+	flags.StringVar(&accessCheck.Resource, "resource", "pods", "Resource checked by the SelfSubjectAccessReview.")
+	flags.StringVarP(&accessCheck.Namespace, "namespace", "n", "", "Namespace checked by the SelfSubjectAccessReview, all namespaces if not given.")
+
+	return cmd
+}
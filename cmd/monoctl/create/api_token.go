@@ -21,16 +21,18 @@ import (
 
 	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
 	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/output"
 	"github.com/finleap-connect/monoctl/internal/usecases"
 	"github.com/finleap-connect/monoctl/internal/util"
 	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
-	apiGateway "github.com/finleap-connect/monoskope/pkg/api/gateway"
 	"github.com/spf13/cobra"
 )
 
 func NewCreateAPITokenCmd() *cobra.Command {
 	var userId string
 	var scopes []string
+	var outputFormat string
+	outputOptions := &usecases.APITokenOutputOptions{}
 	validity := time.Hour * 24
 
 	cmd := &cobra.Command{
@@ -38,10 +40,16 @@ func NewCreateAPITokenCmd() *cobra.Command {
 		Short: "Let m8 issue an API token.",
 		Long:  `Retrieve an API token issued by the m8 control plane.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := output.ParseOutputFormat(outputFormat)
+			if err != nil {
+				return err
+			}
+			outputOptions.Format = format
+
 			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
 
 			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
-				return usecases.NewCreateAPITokenUsecase(configManager, userId, scopes, validity).Run(ctx)
+				return usecases.NewCreateAPITokenUsecase(configManager, userId, scopes, validity, outputOptions).Run(ctx)
 			})
 		},
 	}
@@ -50,15 +58,16 @@ func NewCreateAPITokenCmd() *cobra.Command {
 	flags.StringVarP(&userId, "user", "u", "", "Specify the name or UUID of the user for whom the token should be issued. If not a UUID it will be treated as username.")
 	util.PanicOnError(cmd.MarkFlagRequired("user"))
 
-	avialbleScopes := make([]string, len(apiGateway.AuthorizationScope_name))
-	for _, value := range apiGateway.AuthorizationScope_name {
-		avialbleScopes[apiGateway.AuthorizationScope_value[value]] = value
-	}
-	scopesUsage := "Specify the scopes for which the token should be valid.\nAvailable scopes: " + strings.Join(avialbleScopes, ", ")
+	scopesUsage := "Specify the scopes for which the token should be valid.\nAvailable scopes: " + strings.Join(usecases.AvailableAPITokenScopes(), ", ")
 	flags.StringSliceVarP(&scopes, "scopes", "s", scopes, scopesUsage)
 	util.PanicOnError(cmd.MarkFlagRequired("scopes"))
 
 	flags.DurationVarP(&validity, "validity", "v", validity, "Specify the validity period of the token.")
 
+	flags.StringVarP(&outputFormat, "output", "o", "", "Output format. One of: json. By default only the token is printed.")
+	flags.StringVar(&outputOptions.File, "output-file", "", "Write the output to the given file with 0600 permissions instead of printing it.")
+	flags.StringVar(&outputOptions.KubeconfigUser, "kubeconfig-user", "", "Store the token as user with the given name in the kubeconfig.")
+	flags.BoolVar(&outputOptions.Env, "env", false, "Print the token as export statement of the MONOCTL_TOKEN environment variable.")
+
 	return cmd
 }
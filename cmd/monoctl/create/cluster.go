@@ -24,8 +24,9 @@ import (
 
 	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
 	"github.com/finleap-connect/monoctl/internal/config"
+	mk8s "github.com/finleap-connect/monoctl/internal/k8s"
+	"github.com/finleap-connect/monoctl/internal/prompt"
 	"github.com/finleap-connect/monoctl/internal/usecases"
-	"github.com/finleap-connect/monoctl/internal/util"
 	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
 	"github.com/finleap-connect/monoskope/pkg/k8s"
 	"github.com/spf13/cobra"
@@ -35,16 +36,58 @@ func NewCreateClusterCmd() *cobra.Command {
 	var (
 		apiServerAddress string
 		caCertBundleFile string
+		discoverCA       bool
+		caFingerprint    string
+		fromKubeconfig   string
+		contextPattern   string
+		dryRun           bool
+		bootstrapOptions = &usecases.BootstrapOptions{}
 	)
 
 	cmd := &cobra.Command{
-		Use:   "cluster <NAME>",
+		Use:   "cluster [NAME]",
 		Short: "Create cluster.",
-		Long:  `Creates a Kubernetes cluster.`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Creates a Kubernetes cluster.
+
+The CA certificate bundle of the cluster is either read from file or discovered by connecting to the API server.
+If the API server presents its serving certificate only, the CA is read from the kube-public/cluster-info ConfigMap.
+Discovered CA certificates are shown and have to be confirmed, or match the fingerprint given by --ca-fingerprint.
+
+With --from-kubeconfig the clusters of a kubeconfig are created instead, taking the server and CA bundle of each.
+Clusters which already exist are skipped, --context limits the import to the clusters of the contexts matching the glob.
+
+With --bootstrap-out the configuration of the KubeAPIServer onboarding the new cluster, trusting the Monoskope gateway
+as OIDC issuer, is written to the given directory. It can be regenerated by "monoctl get cluster-bootstrap".`,
+		Example: `  monoctl create cluster my-cluster -a https://api.my-cluster.example.com -c ca.pem
+  monoctl create cluster my-cluster -a https://api.my-cluster.example.com --discover-ca --bootstrap-out ./my-cluster-bootstrap
+  monoctl create cluster --from-kubeconfig ~/.kube/config --context 'prod-*' --dry-run`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 
+			if fromKubeconfig != "" {
+				if len(args) != 0 {
+					return errors.New("no name must be given with --from-kubeconfig")
+				}
+				kubeconfigFile, err := mk8s.ExpandPath(fromKubeconfig)
+				if err != nil {
+					return err
+				}
+				configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+				return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+					return usecases.NewImportClustersUseCase(configManager.GetConfig(), kubeconfigFile, contextPattern, dryRun).Run(ctx)
+				})
+			}
+			if contextPattern != "" || dryRun {
+				return errors.New("--context and --dry-run require --from-kubeconfig")
+			}
+			if len(args) != 1 {
+				return errors.New("name of the cluster must be given")
+			}
+			if apiServerAddress == "" {
+				return errors.New("--api-server-address must be given")
+			}
+
 			name := args[0]
 
 			u, err := url.Parse(apiServerAddress)
@@ -61,14 +104,36 @@ func NewCreateClusterCmd() *cobra.Command {
 			}
 			name = sanitizedName
 
-			caCertBundle, err := os.ReadFile(caCertBundleFile)
-			if err != nil {
-				return fmt.Errorf("failed to read CA certificates from '%s': %s", caCertBundleFile, err)
+			if !discoverCA && caCertBundleFile == "" {
+				return errors.New("either --ca-filepath or --discover-ca must be given")
+			}
+			if caFingerprint != "" && !discoverCA {
+				return errors.New("--ca-fingerprint requires --discover-ca")
+			}
+
+			var caCertBundle []byte
+			if discoverCA {
+				var confirm func(string) bool
+				if prompt.IsTerminal() {
+					confirm = prompt.Confirm
+				}
+				caCertBundle, err = mk8s.DiscoverCABundle(cmd.Context(), apiServerAddress, caFingerprint, cmd.OutOrStdout(), confirm)
+				if err != nil {
+					return err
+				}
+			} else {
+				caCertBundle, err = os.ReadFile(caCertBundleFile)
+				if err != nil {
+					return fmt.Errorf("failed to read CA certificates from '%s': %s", caCertBundleFile, err)
+				}
+				caCertBundle = []byte(strings.TrimSpace(string(caCertBundle)))
 			}
-			caCertBundle = []byte(strings.TrimSpace(string(caCertBundle)))
 
 			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
 			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+				if bootstrapOptions.Dir != "" {
+					return usecases.NewCreateClusterWithBootstrapUseCase(configManager.GetConfig(), name, apiServerAddress, caCertBundle, bootstrapOptions).Run(ctx)
+				}
 				return usecases.NewCreateClusterUseCase(configManager.GetConfig(), name, apiServerAddress, caCertBundle).Run(ctx)
 			})
 		},
@@ -78,8 +143,18 @@ func NewCreateClusterCmd() *cobra.Command {
 
 	flags.StringVarP(&caCertBundleFile, "ca-filepath", "c", "", "Path to the file containing the CA certificate bundle of the cluster in PEM format.")
 	flags.StringVarP(&apiServerAddress, "api-server-address", "a", "", "Address of the KubeAPIServer of the cluster.")
-	util.PanicOnError(cmd.MarkFlagRequired("ca-filepath"))
-	util.PanicOnError(cmd.MarkFlagRequired("api-server-address"))
+	flags.BoolVar(&discoverCA, "discover-ca", false, "Discover the CA certificate bundle by connecting to the KubeAPIServer.")
+	flags.StringVar(&caFingerprint, "ca-fingerprint", "", "SHA-256 fingerprint of the discovered CA certificate to trust without confirmation.")
+	flags.StringVar(&fromKubeconfig, "from-kubeconfig", "", "Create the clusters of the kubeconfig file.")
+	flags.StringVar(&contextPattern, "context", "", "Glob of the contexts whose clusters are created from the kubeconfig.")
+	flags.BoolVar(&dryRun, "dry-run", false, "Only show what would be created from the kubeconfig.")
+	flags.StringVar(&bootstrapOptions.Dir, "bootstrap-out", "", "Directory to write the onboarding manifests of the new cluster to.")
+	flags.StringVar(&bootstrapOptions.IssuerURL, "bootstrap-issuer-url", "", "Issuer URL of the Monoskope gateway, defaults to the issuer of the current session.")
+	flags.StringVar(&bootstrapOptions.UsernamePrefix, "bootstrap-username-prefix", usecases.DefaultBootstrapUsernamePrefix, "Username prefix, has to match the one of the RBAC reconciler of Monoskope.")
+	cmd.MarkFlagsMutuallyExclusive("ca-filepath", "discover-ca")
+	for _, flag := range []string{"api-server-address", "ca-filepath", "discover-ca", "bootstrap-out"} {
+		cmd.MarkFlagsMutuallyExclusive("from-kubeconfig", flag)
+	}
 
 	return cmd
 }
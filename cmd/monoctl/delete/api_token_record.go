@@ -0,0 +1,44 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delete
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	"github.com/spf13/cobra"
+)
+
+func NewDeleteAPITokenRecordCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api-token-record <ID>",
+		Short: "Remove the local record of an API token, the token stays valid until it expires.",
+		Long: `Removes the record of the API token with the given ID from the monoconfig. The ID of a token can be looked up with "monoctl get api-token-records" or "monoctl describe api-token".
+Monoskope can not revoke API tokens, the token itself is NOT revoked and stays valid until it expires.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			if err := configManager.LoadConfig(); err != nil {
+				return fmt.Errorf("failed loading monoconfig: %w", err)
+			}
+			return usecases.NewDeleteAPITokenRecordUseCase(configManager, args[0]).Run(context.Background())
+		},
+	}
+
+	return cmd
+}
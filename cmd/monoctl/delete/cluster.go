@@ -25,17 +25,28 @@ import (
 )
 
 func NewDeleteClusterCmd() *cobra.Command {
+	var cascade, yes bool
+
 	cmd := &cobra.Command{
 		Use:   "cluster <NAME>",
 		Short: "Delete cluster.",
-		Long:  `Deletes a cluster.`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Deletes a cluster. Everything deleted is listed and has to be confirmed.
+With --cascade the access of tenants to the cluster is deleted first.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := newDeleteOptions("cluster", args[0], cascade, yes)
+			if err != nil {
+				return err
+			}
+
 			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
 			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
-				return usecases.NewDeleteClusterUseCase(configManager.GetConfig(), args[0]).Run(ctx)
+				return usecases.NewDeleteClusterUseCase(configManager.GetConfig(), args[0], options).Run(ctx)
 			})
 		},
 	}
+
+	addDeleteFlags(cmd, "cluster access of tenants", &cascade, &yes)
+
 	return cmd
 }
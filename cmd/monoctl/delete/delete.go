@@ -15,6 +15,11 @@
 package delete
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/finleap-connect/monoctl/internal/prompt"
+	"github.com/finleap-connect/monoctl/internal/usecases"
 	"github.com/spf13/cobra"
 )
 
@@ -31,6 +36,32 @@ func NewDeleteCmd() *cobra.Command {
 	cmd.AddCommand(NewDeleteUserRoleBindingCmd())
 	cmd.AddCommand(NewDeleteClusterCmd())
 	cmd.AddCommand(NewDeleteUserCmd())
+	cmd.AddCommand(NewDeleteAPITokenRecordCmd())
 
 	return cmd
 }
+
+// addDeleteFlags adds the flags of deleting an entity having dependents
+func addDeleteFlags(cmd *cobra.Command, dependents string, cascade, yes *bool) {
+	flags := cmd.Flags()
+	flags.BoolVar(cascade, "cascade", false, fmt.Sprintf("Delete the %s depending on it first.", dependents))
+	flags.BoolVarP(yes, "yes", "y", false, "Delete without asking for confirmation.")
+}
+
+// newDeleteOptions returns the options asking for confirmation unless yes is set
+func newDeleteOptions(kind, name string, cascade, yes bool) (*usecases.DeleteOptions, error) {
+	options := &usecases.DeleteOptions{
+		Cascade:       cascade,
+		ResumeCommand: fmt.Sprintf("monoctl delete %s %s --yes", kind, name),
+	}
+	if cascade {
+		options.ResumeCommand += " --cascade"
+	}
+	if !yes {
+		if !prompt.IsTerminal() {
+			return nil, errors.New("deletion must be confirmed, use --yes when not running interactively")
+		}
+		options.Confirm = prompt.Confirm
+	}
+	return options, nil
+}
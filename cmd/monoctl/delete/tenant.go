@@ -25,17 +25,28 @@ import (
 )
 
 func NewDeleteTenantCmd() *cobra.Command {
+	var cascade, yes bool
+
 	cmd := &cobra.Command{
 		Use:   "tenant <NAME>",
 		Short: "Delete tenant.",
-		Long:  `Deletes a tenant.`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Deletes a tenant. Everything deleted is listed and has to be confirmed.
+With --cascade the rolebindings of its members on the tenant and its cluster access are deleted first.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := newDeleteOptions("tenant", args[0], cascade, yes)
+			if err != nil {
+				return err
+			}
+
 			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
 			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
-				return usecases.NewDeleteTenantUseCase(configManager.GetConfig(), args[0]).Run(ctx)
+				return usecases.NewDeleteTenantUseCase(configManager.GetConfig(), args[0], options).Run(ctx)
 			})
 		},
 	}
+
+	addDeleteFlags(cmd, "rolebindings and cluster access", &cascade, &yes)
+
 	return cmd
 }
@@ -25,17 +25,28 @@ import (
 )
 
 func NewDeleteUserCmd() *cobra.Command {
+	var cascade, yes bool
+
 	cmd := &cobra.Command{
 		Use:   "user <E-MAIL ADDRESS>",
 		Short: "Delete user.",
-		Long:  `Deletes a user.`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Deletes a user. Everything deleted is listed and has to be confirmed.
+With --cascade the rolebindings of the user are deleted first.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := newDeleteOptions("user", args[0], cascade, yes)
+			if err != nil {
+				return err
+			}
+
 			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
 			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
-				return usecases.NewDeleteUserUseCase(configManager.GetConfig(), args[0]).Run(ctx)
+				return usecases.NewDeleteUserUseCase(configManager.GetConfig(), args[0], options).Run(ctx)
 			})
 		},
 	}
+
+	addDeleteFlags(cmd, "rolebindings", &cascade, &yes)
+
 	return cmd
 }
@@ -0,0 +1,56 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package describe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	"github.com/spf13/cobra"
+)
+
+func NewDescribeAPITokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api-token <TOKEN>",
+		Short: "Decode and validate API token.",
+		Long: `Decodes the given API token and validates its claims. Use "-" to read the token from stdin.
+The signature is not verified, this is done by the m8 control plane whenever the token is used.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			if err := configManager.LoadConfig(); err != nil {
+				return fmt.Errorf("failed loading monoconfig: %w", err)
+			}
+
+			rawToken := args[0]
+			if rawToken == "-" {
+				data, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					return err
+				}
+				rawToken = string(data)
+			}
+
+			return usecases.NewDescribeAPITokenUseCase(configManager.GetConfig(), rawToken).Run(context.Background())
+		},
+	}
+
+	return cmd
+}
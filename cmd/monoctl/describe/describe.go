@@ -0,0 +1,36 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package describe
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewDescribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "describe",
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Short:                 "Show details of anything within Monoskope",
+		Long:                  `Show details of anything within Monoskope`,
+	}
+
+	cmd.AddCommand(NewDescribeAPITokenCmd())
+	cmd.AddCommand(NewDescribeClusterCmd())
+	cmd.AddCommand(NewDescribeTenantCmd())
+	cmd.AddCommand(NewDescribeUserCmd())
+
+	return cmd
+}
@@ -0,0 +1,44 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package get
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	"github.com/spf13/cobra"
+)
+
+func NewGetAPITokenRecordsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "api-token-records",
+		Aliases: []string{"api-token-record"},
+		Short:   "Get local records of API tokens.",
+		Long: `Get the records of API tokens issued via this monoctl configuration. The records are kept in the monoconfig, the tokens themselves are not stored.
+Monoskope does not keep track of issued API tokens, tokens issued otherwise are not listed. Expired tokens are shown with --deleted.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			if err := configManager.LoadConfig(); err != nil {
+				return fmt.Errorf("failed loading monoconfig: %w", err)
+			}
+			return usecases.NewGetAPITokenRecordsUseCase(configManager.GetConfig(), getOutputOptions()).Run(context.Background())
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package get
+
+import (
+	"context"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
+	"github.com/spf13/cobra"
+)
+
+func NewGetClusterBootstrapCmd() *cobra.Command {
+	options := &usecases.BootstrapOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "cluster-bootstrap <NAME>",
+		Short: "Get the onboarding manifests of a cluster.",
+		Long: `Renders the configuration onboarding a cluster to Monoskope. Monoskope deploys nothing to the cluster, its
+KubeAPIServer is configured to trust the Monoskope gateway as OIDC issuer of cluster tokens instead:
+as kubeadm ClusterConfiguration and as patch of the oidcConfig of a Gardener shoot.
+Roles are bound to the users within the cluster by the RBAC reconciler of Monoskope,
+the username prefix has to match the one it is configured with.
+The manifests are written to stdout unless --out is given.`,
+		Example: `  monoctl get cluster-bootstrap my-cluster
+  monoctl get cluster-bootstrap my-cluster --out ./my-cluster-bootstrap`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+				return usecases.NewGetClusterBootstrapUseCase(configManager.GetConfig(), args[0], options).Run(ctx)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.Dir, "out", "", "Directory to write the manifests to.")
+	flags.StringVar(&options.IssuerURL, "issuer-url", "", "Issuer URL of the Monoskope gateway, defaults to the issuer of the current session.")
+	flags.StringVar(&options.UsernamePrefix, "username-prefix", usecases.DefaultBootstrapUsernamePrefix, "Username prefix, has to match the one of the RBAC reconciler of Monoskope.")
+
+	return cmd
+}
@@ -17,6 +17,7 @@ package get
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
 	"github.com/finleap-connect/monoctl/internal/config"
@@ -27,26 +28,51 @@ import (
 
 func NewGetClusterCredentials() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "cluster-credentials [CLUSTER] [ROLE]",
+		Use:   "cluster-credentials [CLUSTER] ROLE",
 		Short: "Get cluster credentials.",
-		Long:  `Get credentials for a specific cluster known to the m8 control plane.`,
-		Args:  cobra.ExactArgs(2),
+		Long: `Get credentials for a specific cluster known to the m8 control plane.
+The cluster is given by name or id, the role is checked against your cluster access before requesting a token.
+
+This command is called by kubectl as credential plugin and writes nothing but the ExecCredential to stdout.
+Valid cached credentials are served without contacting the m8 control plane.
+If kubectl passes the cluster information (provideClusterInfo) the cluster is taken from it and can be omitted.
+The version of the ExecCredential is the one requested by kubectl, or execCredentialApiVersion of the monoconfig.`,
+		Args:          cobra.RangeArgs(1, 2),
+		SilenceUsage:  true,
+		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clusterName := args[0]
-			if clusterName == "" {
-				return errors.New("cluster must be specified")
+			var clusterName string
+			clusterRole := args[len(args)-1]
+			if len(args) == 2 {
+				clusterName = args[0]
 			}
-
-			clusterRole := args[1]
 			if clusterRole == "" {
-				return errors.New("role must be specified")
+				return credentialsError(cmd, errors.New("role must be specified"))
 			}
 
 			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
-			return auth_util.RetryOnAuthFailSilently(cmd.Context(), configManager, func(ctx context.Context) error {
+
+			// Serve cached credentials without dialing the gateway or locking the auth flow
+			if !flags.ForceAuth && configManager.LoadConfig() == nil {
+				err := usecases.NewGetCachedClusterCredentialsUseCase(configManager, clusterName, clusterRole).Run(cmd.Context())
+				if !errors.Is(err, usecases.ErrClusterCredentialsNotCached) {
+					return credentialsError(cmd, err)
+				}
+			}
+
+			err := auth_util.RetryOnAuthFailSilently(cmd.Context(), configManager, func(ctx context.Context) error {
 				return usecases.NewGetClusterCredentialsUseCase(configManager, clusterName, clusterRole).Run(ctx)
 			})
+			return credentialsError(cmd, err)
 		},
 	}
 	return cmd
 }
+
+// credentialsError writes the error to stderr in a single line kubectl shows to the user
+func credentialsError(cmd *cobra.Command, err error) error {
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "monoctl: failed getting cluster credentials: %v\n", err)
+	}
+	return err
+}
@@ -54,8 +54,10 @@ func NewGetCmd() *cobra.Command {
 	cmd.AddCommand(NewGetRoleBindingsCmd())
 	cmd.AddCommand(NewGetTenantUsersCmd())
 	cmd.AddCommand(NewGetClusterCredentials())
+	cmd.AddCommand(NewGetClusterBootstrapCmd())
 	cmd.AddCommand(NewGetClusterAccess())
 	cmd.AddCommand(NewGetAuditLogCmd())
+	cmd.AddCommand(NewGetAPITokenRecordsCmd())
 
 	flags := cmd.PersistentFlags()
 	flags.BoolVarP(&showDeleted, "deleted", "d", false, "Show deleted resources.")
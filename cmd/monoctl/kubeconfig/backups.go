@@ -0,0 +1,109 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/k8s"
+	"github.com/finleap-connect/monoctl/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func NewBackupsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "backups",
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Short:                 "Manage the backups monoctl takes before changing a kubeconfig",
+		Long: `Manage the backups monoctl takes before changing a kubeconfig.
+
+The backups are stored in the directory .monoctl-backups next to the kubeconfig file.
+The number of backups kept can be configured with kubeconfigBackupRetention in the monoconfig, a negative value disables backups.`,
+	}
+	cmd.AddCommand(NewListBackupsCmd())
+	return cmd
+}
+
+func NewListBackupsCmd() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the backups of the kubeconfig",
+		Long:  `List the backups of the kubeconfig, the newest first.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeConfig, err := newKubeConfig(file)
+			if err != nil {
+				return err
+			}
+			backups, err := kubeConfig.ListBackups()
+			if err != nil {
+				return err
+			}
+			if len(backups) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No backups of %s found.\n", kubeConfig.ConfigPath)
+				return nil
+			}
+
+			var data [][]interface{}
+			for _, backup := range backups {
+				data = append(data, []interface{}{backup.Id, time.Since(backup.Created), backup.Size})
+			}
+			tbl, err := output.NewTableFactory().
+				SetHeader([]string{"ID", "AGE", "SIZE"}).
+				SetSortColumn("ID").
+				SetSortOrder(output.Descending).
+				SetColumnFormatter("AGE", output.DefaultAgeColumnFormatter()).
+				SetData(data).
+				ToTable()
+			if err != nil {
+				return err
+			}
+			tbl.Render()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The kubeconfig file whose backups to list.")
+
+	return cmd
+}
+
+// newKubeConfig returns the kubeconfig given by the user or the one last written by monoctl
+func newKubeConfig(file string) (*k8s.KubeConfig, error) {
+	kubeConfig := k8s.NewKubeConfig()
+	kubeConfig.SetPath(file)
+
+	configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+	if err := configManager.LoadConfig(); err == nil {
+		conf := configManager.GetConfig()
+		if file == "" {
+			kubeConfig.SetPath(conf.KubeConfigPath)
+		}
+		if conf.KubeconfigBackupRetention != 0 {
+			kubeConfig.BackupRetention = conf.KubeconfigBackupRetention
+		}
+	}
+
+	if err := kubeConfig.ResolvePath(); err != nil {
+		return nil, err
+	}
+	return kubeConfig, nil
+}
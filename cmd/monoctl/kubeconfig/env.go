@@ -0,0 +1,85 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"fmt"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/k8s"
+	"github.com/spf13/cobra"
+)
+
+const kubeConfigEnvVar = "KUBECONFIG"
+
+func NewEnvCmd() *cobra.Command {
+	var (
+		shellName string
+		file      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Print the KUBECONFIG export for the kubeconfig written by monoctl",
+		Long: `Print the command setting KUBECONFIG to the kubeconfig written by monoctl followed by the files currently used by kubectl.
+
+By default the kubeconfig last written by "monoctl update kubeconfig" is used, otherwise ` + k8s.StandaloneKubeConfigPath + `.
+The shell is detected from $SHELL, supported shells are bash, zsh, fish and powershell.`,
+		Example: `  # bash or zsh
+  eval "$(monoctl kubeconfig env)"
+
+  # fish
+  monoctl kubeconfig env --shell fish | source
+
+  # PowerShell
+  monoctl kubeconfig env --shell powershell | Invoke-Expression`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shell := k8s.DetectShell()
+			if shellName != "" {
+				var err error
+				if shell, err = k8s.ParseShell(shellName); err != nil {
+					return err
+				}
+			}
+
+			if file == "" {
+				file = k8s.StandaloneKubeConfigPath
+				configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+				if err := configManager.LoadConfig(); err == nil && configManager.GetConfig().KubeConfigPath != "" {
+					file = configManager.GetConfig().KubeConfigPath
+				}
+			}
+
+			value, err := k8s.KubeConfigEnv(file)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintln(out, k8s.ExportEnv(shell, kubeConfigEnvVar, value))
+			fmt.Fprintln(out, "# Run this command to configure your shell:")
+			fmt.Fprintf(out, "# %s\n", k8s.EvalHint(shell, cmd.CommandPath()+" --shell "+string(shell)))
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&shellName, "shell", "", "The shell to print the export for, one of bash, zsh, fish and powershell. Detected from $SHELL if not set.")
+	flags.StringVarP(&file, "file", "f", "", "The kubeconfig file to add to KUBECONFIG.")
+
+	return cmd
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewKubeconfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "kubeconfig",
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Short:                 "Helpers for the kubeconfig written by monoctl",
+		Long:                  `Helpers for the kubeconfig written by monoctl`,
+	}
+	cmd.AddCommand(NewEnvCmd())
+	cmd.AddCommand(NewBackupsCmd())
+	cmd.AddCommand(NewRestoreCmd())
+	return cmd
+}
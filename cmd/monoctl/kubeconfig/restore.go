@@ -0,0 +1,50 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRestoreCmd() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "restore <id>",
+		Short: "Restore a backup of the kubeconfig",
+		Long: `Restore a backup of the kubeconfig. Use "monoctl kubeconfig backups list" to find the id of the backup.
+
+The current kubeconfig is backed up before, so the restore can be undone.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeConfig, err := newKubeConfig(file)
+			if err != nil {
+				return err
+			}
+			backup, err := kubeConfig.RestoreBackup(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Restored %s from backup '%s' taken at %s.\n", kubeConfig.ConfigPath, backup.Id, backup.Created.Local().Format("2006-01-02 15:04:05"))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The kubeconfig file to restore.")
+
+	return cmd
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"time"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
+	"github.com/spf13/cobra"
+)
+
+func NewRestoreClusterCmd() *cobra.Command {
+	var before time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "cluster <NAME|ID>",
+		Short: "Restore a deleted cluster.",
+		Long: `Re-creates the deleted cluster with the given name or id and grants the tenants which had access to it before the deletion access again.
+The restored cluster gets a new id.`,
+		Example: `  monoctl restore cluster my-cluster
+  monoctl restore cluster my-cluster --before 1h`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+				return usecases.NewRestoreClusterUseCase(configManager.GetConfig(), args[0], before).Run(ctx)
+			})
+		},
+	}
+
+	addBeforeFlag(cmd, &before)
+
+	return cmd
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	"github.com/spf13/cobra"
+)
+
+func NewRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "restore",
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Short:                 "Restore deleted entities within Monoskope",
+		Long: `Restore deleted entities within Monoskope.
+Monoskope has no undelete, the entity is re-created with a new id and the rolebindings and cluster access it had before the deletion are reattached as found in the audit log.
+A deleted rolebinding is re-created by its user, role and scope as the audit log does not show its id.`,
+	}
+
+	cmd.AddCommand(NewRestoreUserCmd())
+	cmd.AddCommand(NewRestoreTenantCmd())
+	cmd.AddCommand(NewRestoreClusterCmd())
+	cmd.AddCommand(NewRestoreRoleBindingCmd())
+
+	return cmd
+}
+
+// addBeforeFlag adds the flag setting how long before the deletion the dependents are looked up
+func addBeforeFlag(cmd *cobra.Command, before *time.Duration) {
+	cmd.Flags().DurationVar(before, "before", usecases.DefaultRestoreBefore, "How long before the deletion to look up what to reattach in the audit log.")
+}
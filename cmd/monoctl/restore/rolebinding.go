@@ -0,0 +1,64 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"time"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	"github.com/finleap-connect/monoctl/internal/util"
+	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
+	"github.com/spf13/cobra"
+)
+
+func NewRestoreRoleBindingCmd() *cobra.Command {
+	var (
+		role   string
+		scope  string
+		tenant string
+		before time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rolebinding <EMAIL>",
+		Short: "Restore a deleted rolebinding.",
+		Long: `Re-creates the most recently deleted rolebinding of the user with the given role and scope as found in the audit log.
+The audit log does not show the tenant of a rolebinding. It is the tenant the user had the role on before the deletion
+and has no longer, --tenant chooses one if there are several.`,
+		Example: `  monoctl restore rolebinding jane.doe@monoskope.io --role admin --scope system
+  monoctl restore rolebinding jane.doe@monoskope.io --role user --scope tenant --tenant my-tenant`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+				return usecases.NewRestoreRoleBindingUseCase(configManager.GetConfig(), args[0], role, scope, tenant, before).Run(ctx)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&role, "role", "r", "", "Role of the rolebinding.")
+	flags.StringVarP(&scope, "scope", "s", "", "Scope of the rolebinding.")
+	flags.StringVar(&tenant, "tenant", "", "Tenant the rolebinding was scoped to.")
+	addBeforeFlag(cmd, &before)
+
+	util.PanicOnError(cmd.MarkFlagRequired("role"))
+	util.PanicOnError(cmd.MarkFlagRequired("scope"))
+
+	return cmd
+}
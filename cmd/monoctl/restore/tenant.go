@@ -0,0 +1,49 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"time"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
+	"github.com/spf13/cobra"
+)
+
+func NewRestoreTenantCmd() *cobra.Command {
+	var before time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "tenant <NAME|ID>",
+		Short: "Restore a deleted tenant.",
+		Long:  `Re-creates the deleted tenant with the given name or id, reattaches the rolebindings of its members and grants it access to the clusters it had access to before the deletion.`,
+		Example: `  monoctl restore tenant my-tenant
+  monoctl restore tenant my-tenant --before 1h`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+				return usecases.NewRestoreTenantUseCase(configManager.GetConfig(), args[0], before).Run(ctx)
+			})
+		},
+	}
+
+	addBeforeFlag(cmd, &before)
+
+	return cmd
+}
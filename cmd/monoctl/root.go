@@ -19,12 +19,16 @@ import (
 	"time"
 
 	"github.com/finleap-connect/monoctl/cmd/monoctl/auth"
+	"github.com/finleap-connect/monoctl/cmd/monoctl/check"
 	conf "github.com/finleap-connect/monoctl/cmd/monoctl/config"
 	"github.com/finleap-connect/monoctl/cmd/monoctl/create"
 	"github.com/finleap-connect/monoctl/cmd/monoctl/delete"
+	"github.com/finleap-connect/monoctl/cmd/monoctl/describe"
 	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
 	"github.com/finleap-connect/monoctl/cmd/monoctl/get"
 	"github.com/finleap-connect/monoctl/cmd/monoctl/grant"
+	"github.com/finleap-connect/monoctl/cmd/monoctl/kubeconfig"
+	"github.com/finleap-connect/monoctl/cmd/monoctl/restore"
 	"github.com/finleap-connect/monoctl/cmd/monoctl/revoke"
 	"github.com/finleap-connect/monoctl/cmd/monoctl/update"
 	"github.com/spf13/cobra"
@@ -55,9 +59,14 @@ users and their roles in a Kubernetes multi-cluster environment.`,
 	rootCmd.AddCommand(get.NewGetCmd())
 	rootCmd.AddCommand(create.NewCreateCmd())
 	rootCmd.AddCommand(update.NewUpdateCmd())
+	rootCmd.AddCommand(kubeconfig.NewKubeconfigCmd())
+	rootCmd.AddCommand(NewUseCmd())
 	rootCmd.AddCommand(delete.NewDeleteCmd())
+	rootCmd.AddCommand(describe.NewDescribeCmd())
 	rootCmd.AddCommand(grant.NewGrantCmd())
 	rootCmd.AddCommand(revoke.NewRevokeCmd())
+	rootCmd.AddCommand(check.NewCheckCmd())
+	rootCmd.AddCommand(restore.NewRestoreCmd())
 
 	return rootCmd
 }
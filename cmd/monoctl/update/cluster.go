@@ -23,6 +23,7 @@ import (
 
 	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
 	"github.com/finleap-connect/monoctl/internal/config"
+	mk8s "github.com/finleap-connect/monoctl/internal/k8s"
 	"github.com/finleap-connect/monoctl/internal/prompt"
 	"github.com/finleap-connect/monoctl/internal/usecases"
 	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
@@ -34,21 +35,30 @@ func NewUpdateClusterCmd() *cobra.Command {
 		newName          string
 		apiServerAddress string
 		caCertBundleFile string
+		discoverCA       bool
+		caFingerprint    string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "cluster <NAME>",
 		Short: "Update cluster.",
-		Long:  `Updates a cluster.`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Updates a cluster.
+
+With --discover-ca the CA certificate bundle is discovered by connecting to the new or current API server.
+If the API server presents its serving certificate only, the CA is read from the kube-public/cluster-info ConfigMap.
+Discovered CA certificates are shown and have to be confirmed, or match the fingerprint given by --ca-fingerprint.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			var caCertBundle []byte
 			name := args[0]
 
-			if caCertBundleFile == "" && newName == "" && apiServerAddress == "" && caCertBundle == nil {
+			if caCertBundleFile == "" && newName == "" && apiServerAddress == "" && !discoverCA {
 				return errors.New("nothing to update")
 			}
+			if caFingerprint != "" && !discoverCA {
+				return errors.New("--ca-fingerprint requires --discover-ca")
+			}
 
 			if caCertBundleFile != "" {
 				caCertBundle, err = os.ReadFile(caCertBundleFile)
@@ -77,6 +87,15 @@ func NewUpdateClusterCmd() *cobra.Command {
 
 			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
 			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+				if discoverCA {
+					return usecases.NewUpdateClusterDiscoverCAUseCase(configManager.GetConfig(), name, newName, apiServerAddress, func(ctx context.Context, apiServerAddress string) ([]byte, error) {
+						var confirm func(string) bool
+						if prompt.IsTerminal() {
+							confirm = prompt.Confirm
+						}
+						return mk8s.DiscoverCABundle(ctx, apiServerAddress, caFingerprint, cmd.OutOrStdout(), confirm)
+					}).Run(ctx)
+				}
 				return usecases.NewUpdateClusterUseCase(configManager.GetConfig(), name, newName, apiServerAddress, caCertBundle).Run(ctx)
 			})
 		},
@@ -87,6 +106,9 @@ func NewUpdateClusterCmd() *cobra.Command {
 	flags.StringVarP(&newName, "new-name", "n", "", "New name of the cluster")
 	flags.StringVarP(&apiServerAddress, "api-server-address", "a", "", "New KubeAPIServer address")
 	flags.StringVarP(&caCertBundleFile, "ca-cert-path", "c", "", "New CA certificate bundle file")
+	flags.BoolVar(&discoverCA, "discover-ca", false, "Discover the new CA certificate bundle by connecting to the KubeAPIServer")
+	flags.StringVar(&caFingerprint, "ca-fingerprint", "", "SHA-256 fingerprint of the discovered CA certificate to trust without confirmation")
+	cmd.MarkFlagsMutuallyExclusive("ca-cert-path", "discover-ca")
 
 	return cmd
 }
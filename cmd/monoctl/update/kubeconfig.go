@@ -16,10 +16,12 @@ package update
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
 	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/k8s"
 	"github.com/finleap-connect/monoctl/internal/usecases"
 	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
 	"github.com/spf13/cobra"
@@ -28,6 +30,10 @@ import (
 var (
 	kubeConfigPath string
 	overwrite      bool
+	dryRun         bool
+	selection      config.KubeconfigSelection
+	allClusters    bool
+	standalone     bool
 )
 
 func NewUpdateKubeconfigCmd() *cobra.Command {
@@ -38,24 +44,66 @@ func NewUpdateKubeconfigCmd() *cobra.Command {
 
 By default the default config file of kubectl will be used ($HOME/.kube/config).
 
-If the KUBECONFIG environment variable is set the file specified will be used. if a list of files is specified you will be asked to choose one.
+If the KUBECONFIG environment variable is set the file specified will be used. if a list of files is specified you will be asked to choose one, without a terminal the first one is used.
 
 You can also specify a custom file by utilising the file option (--file). In this case please make sure to update the KUBECONFIG environment variable.
+
+Use --standalone to write only the entries managed by monoctl to ` + k8s.StandaloneKubeConfigPath + ` instead of touching the kubeconfig of kubectl.
+"monoctl kubeconfig env" prints the KUBECONFIG export to use it together with your other kubeconfig files.
+
+Use --dry-run to see a diff of the changes without writing the kubeconfig.
+Before the kubeconfig is written a backup is taken, see "monoctl kubeconfig backups list" and "monoctl kubeconfig restore".
+
+The clusters and roles written can be restricted by glob patterns with --cluster, --role, --tenant and --exclude.
+Entries outside of the selection are left untouched. The selection is remembered for later updates, use --all to reset it.
+
+The names of contexts, users and namespaces can be configured with Go templates in the kubeconfigNaming section of the monoconfig,
+e.g. "{{.Cluster}}-{{.Role}}" or "{{.Tenant}}-dev". Available fields are Cluster, Role, User and Tenant.
+Contexts which get a new name are renamed and reported.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if standalone {
+				if kubeConfigPath != "" {
+					return errors.New("--standalone and --file can not be used together")
+				}
+				kubeConfigPath = k8s.StandaloneKubeConfigPath
+			}
+
 			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
 			if err := configManager.LoadConfig(); err != nil {
 				return fmt.Errorf("failed loading monoconfig: %w", err)
 			}
-			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
-				return usecases.NewUpdateKubeconfigUseCase(configManager, kubeConfigPath, overwrite).Run(ctx)
+			// Only a selection given on the command line replaces the remembered one
+			var newSelection *config.KubeconfigSelection
+			if allClusters {
+				newSelection = &config.KubeconfigSelection{}
+			} else if !selection.IsEmpty() {
+				newSelection = &selection
+			}
+
+			err := auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+				return usecases.NewUpdateKubeconfigUseCase(configManager, kubeConfigPath, overwrite, dryRun, newSelection).Run(ctx)
 			})
+			if err != nil {
+				return err
+			}
+			if standalone && !dryRun {
+				fmt.Println("Use `eval \"$(monoctl kubeconfig env)\"` to add it to the KUBECONFIG of your shell.")
+			}
+			return nil
 		},
 	}
 
 	flags := cmd.PersistentFlags()
 	flags.StringVarP(&kubeConfigPath, "file", "f", "", "the file, in which kubeconfig will be written")
-	flags.BoolVarP(&overwrite, "overwrite", "o", false, "Overwrites the existing kubeconfig.")
+	flags.BoolVarP(&overwrite, "overwrite", "o", false, "Overwrites the existing kubeconfig. A backup of it is taken before.")
+	flags.BoolVar(&dryRun, "dry-run", false, "Print a diff of the changes instead of writing the kubeconfig.")
+	flags.StringSliceVar(&selection.Clusters, "cluster", nil, "Glob patterns of the names of the clusters to include.")
+	flags.StringSliceVar(&selection.Roles, "role", nil, "Glob patterns of the cluster roles to include.")
+	flags.StringSliceVar(&selection.Tenants, "tenant", nil, "Glob patterns of the names of the tenants whose clusters to include.")
+	flags.StringSliceVar(&selection.Exclude, "exclude", nil, "Glob patterns of cluster names or <cluster>/<role> combinations to exclude.")
+	flags.BoolVar(&allClusters, "all", false, "Include all clusters and roles and forget the remembered selection.")
+	flags.BoolVar(&standalone, "standalone", false, "Write only the entries managed by monoctl to "+k8s.StandaloneKubeConfigPath+".")
 
 	return cmd
 }
@@ -0,0 +1,58 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+
+	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/usecases"
+	auth_util "github.com/finleap-connect/monoctl/internal/util/auth"
+	"github.com/spf13/cobra"
+)
+
+func NewUseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use [CLUSTER] [ROLE]",
+		Short: "Switch the current kubectl context to a cluster",
+		Long: `Switch the current context of the kubeconfig to the given role on the cluster.
+
+Without arguments a list of the accessible clusters and roles is shown to select from, typing filters the list.
+If the role is omitted and you have multiple roles on the cluster you are asked to choose one.
+If the kubeconfig contains no context for the cluster yet, it is updated first.`,
+		Example: `  # select cluster and role interactively
+  monoctl use
+
+  # switch to the admin role on cluster my-cluster
+  monoctl use my-cluster admin`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var clusterName, role string
+			if len(args) > 0 {
+				clusterName = args[0]
+			}
+			if len(args) > 1 {
+				role = args[1]
+			}
+
+			configManager := config.NewLoaderFromExplicitFile(flags.ExplicitFile)
+			return auth_util.RetryOnAuthFail(cmd.Context(), configManager, func(ctx context.Context) error {
+				return usecases.NewUseContextUseCase(configManager, clusterName, role).Run(ctx)
+			})
+		},
+	}
+	return cmd
+}
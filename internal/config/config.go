@@ -17,6 +17,8 @@ package config
 import (
 	"errors"
 	"fmt"
+	"path"
+	"strings"
 	"time"
 
 	keyring "github.com/zalando/go-keyring"
@@ -27,10 +29,26 @@ var (
 	ErrEmptyServer        = errors.New("has no server defined")
 	ErrNoConfigExists     = errors.New("no valid monoconfig found")
 	ErrAlreadyInitialized = errors.New("a configuration already exists")
+	ErrTokenExpiryWarning = errors.New("tokenExpiryWarning must be longer than tokenExpiryOffset, otherwise the token is renewed before the warning")
 )
 
 const (
 	monoctlService = "monoskope/monoctl"
+
+	// DefaultTokenExpiryOffset is the time before the actual expiry from which on the auth token is treated as expired
+	DefaultTokenExpiryOffset = 5 * time.Minute
+	// DefaultTokenExpiryWarning is the time before the expiry of the auth token from which on commands warn about it
+	DefaultTokenExpiryWarning = 30 * time.Minute
+
+	// DefaultContextNameTemplate is the default template for the names of generated kubeconfig contexts
+	DefaultContextNameTemplate = "{{.Cluster}}-{{.Role}}"
+	// DefaultAuthInfoNameTemplate is the default template for the names of generated kubeconfig users
+	DefaultAuthInfoNameTemplate = "{{.Cluster}}-{{.User}}-{{.Role}}"
+	// DefaultNamespaceTemplate is the default template for the namespace of generated kubeconfig contexts
+	DefaultNamespaceTemplate = "{{.User}}"
+
+	// DefaultExecCredentialAPIVersion is the default version of the ExecCredential API used by generated kubeconfig entries
+	DefaultExecCredentialAPIVersion = "client.authentication.k8s.io/v1beta1"
 )
 
 // Config holds the information needed to build connect to remote monoskope instance as a given user
@@ -43,6 +61,21 @@ type Config struct {
 	AuthInformation *AuthInformation `yaml:"authInformation,omitempty"`
 	// ClusterAuthInformation contains information to authenticate against K8s clusters
 	ClusterAuthInformation map[string]*AuthInformation `yaml:"clusterAuthInformation,omitempty"`
+	// TokenExpiryOffset is the time before the actual expiry from which on the auth token is renewed, defaults to DefaultTokenExpiryOffset
+	TokenExpiryOffset time.Duration `yaml:"tokenExpiryOffset,omitempty"`
+	// TokenExpiryWarning is the time before the expiry of the auth token from which on commands warn about it, defaults to DefaultTokenExpiryWarning.
+	// It must be longer than TokenExpiryOffset. A negative value disables the warning.
+	TokenExpiryWarning time.Duration `yaml:"tokenExpiryWarning,omitempty"`
+	// APITokens contains the local records of API tokens issued via monoctl
+	APITokens []*APITokenInformation `yaml:"apiTokens,omitempty"`
+	// KubeconfigSelection restricts the clusters and roles written to the kubeconfig, remembered from the last update
+	KubeconfigSelection *KubeconfigSelection `yaml:"kubeconfigSelection,omitempty"`
+	// KubeconfigNaming contains the templates for the names of generated kubeconfig entries
+	KubeconfigNaming *KubeconfigNaming `yaml:"kubeconfigNaming,omitempty"`
+	// ExecCredentialAPIVersion is the version of the ExecCredential API used by generated kubeconfig entries
+	ExecCredentialAPIVersion string `yaml:"execCredentialApiVersion,omitempty"`
+	// KubeconfigBackupRetention is the number of backups kept of the kubeconfig, zero means the default and negative disables backups
+	KubeconfigBackupRetention int `yaml:"kubeconfigBackupRetention,omitempty"`
 }
 
 // NewConfig is a convenience function that returns a new Config object with defaults
@@ -57,12 +90,78 @@ func (c *Config) Validate() error {
 	if c.Server == "" {
 		return ErrEmptyServer
 	}
+	if c.TokenExpiryWarning > 0 && c.TokenExpiryWarning <= c.GetTokenExpiryOffset() {
+		return ErrTokenExpiryWarning
+	}
 	return nil
 }
 
+// GetExecCredentialAPIVersion returns the configured version of the ExecCredential API, falling back to the default
+func (c *Config) GetExecCredentialAPIVersion() string {
+	if c.ExecCredentialAPIVersion == "" {
+		return DefaultExecCredentialAPIVersion
+	}
+	return c.ExecCredentialAPIVersion
+}
+
+// GetKubeconfigNaming returns the configured naming templates of kubeconfig entries, falling back to the defaults
+func (c *Config) GetKubeconfigNaming() *KubeconfigNaming {
+	naming := &KubeconfigNaming{
+		Context:   DefaultContextNameTemplate,
+		AuthInfo:  DefaultAuthInfoNameTemplate,
+		Namespace: DefaultNamespaceTemplate,
+	}
+	if c.KubeconfigNaming != nil {
+		if c.KubeconfigNaming.Context != "" {
+			naming.Context = c.KubeconfigNaming.Context
+		}
+		if c.KubeconfigNaming.AuthInfo != "" {
+			naming.AuthInfo = c.KubeconfigNaming.AuthInfo
+		}
+		if c.KubeconfigNaming.Namespace != "" {
+			naming.Namespace = c.KubeconfigNaming.Namespace
+		}
+	}
+	return naming
+}
+
+// GetTokenExpiryOffset returns the configured offset before the expiry from which on the auth token is treated as expired
+func (c *Config) GetTokenExpiryOffset() time.Duration {
+	if c.TokenExpiryOffset <= 0 {
+		return DefaultTokenExpiryOffset
+	}
+	return c.TokenExpiryOffset
+}
+
+// GetTokenExpiryWarning returns the configured time before the expiry of the auth token from which on commands warn about it.
+// If not configured, the default is moved back accordingly if the configured offset is not shorter than it.
+func (c *Config) GetTokenExpiryWarning() time.Duration {
+	if c.TokenExpiryWarning == 0 {
+		if offset := c.GetTokenExpiryOffset(); offset >= DefaultTokenExpiryWarning {
+			return offset + DefaultTokenExpiryWarning - DefaultTokenExpiryOffset
+		}
+		return DefaultTokenExpiryWarning
+	}
+	return c.TokenExpiryWarning
+}
+
+// TokenExpiresSoon checks if the auth token is still valid but expires within the configured warning window
+func (c *Config) TokenExpiresSoon() bool {
+	warning := c.GetTokenExpiryWarning()
+	if warning < 0 || !c.HasAuthInformation() || !c.AuthInformation.IsValidExact() {
+		return false
+	}
+	return c.AuthInformation.IsTokenExpired(warning)
+}
+
+// IsAuthInformationValid checks that the auth token is not empty and is not expired with the configured offset
+func (c *Config) IsAuthInformationValid() bool {
+	return c.HasAuthInformation() && c.AuthInformation.IsValid(c.GetTokenExpiryOffset())
+}
+
 func (c *Config) StoreToken() error {
 	if c.HasAuthInformation() {
-		if c.AuthInformation.IsValid() {
+		if c.IsAuthInformationValid() {
 			if err := keyring.Set(monoctlService, c.AuthInformation.Username, c.AuthInformation.Token); err != nil {
 				return err
 			}
@@ -77,7 +176,7 @@ func (c *Config) StoreToken() error {
 
 	cleanedAuthInfos := make(map[string]*AuthInformation)
 	for key, authInfo := range c.ClusterAuthInformation {
-		if authInfo.IsValid() {
+		if authInfo.IsValid(c.GetTokenExpiryOffset()) {
 			if err := keyring.Set(monoctlService, key, authInfo.Token); err != nil {
 				return err
 			}
@@ -122,6 +221,25 @@ func (c *Config) GetClusterAuthInformation(clusterId, username, role string) *Au
 	return c.ClusterAuthInformation[fmt.Sprintf("%s/%s/%s", clusterId, username, role)]
 }
 
+// ParseClusterAuthKey returns the cluster id, username and role of a key of ClusterAuthInformation
+func ParseClusterAuthKey(key string) (clusterId, username, role string, ok bool) {
+	first := strings.Index(key, "/")
+	last := strings.LastIndex(key, "/")
+	if first < 0 || first == last {
+		return "", "", "", false
+	}
+	return key[:first], key[first+1 : last], key[last+1:], true
+}
+
+// RemoveClusterAuthInformation removes the cluster auth information of the given key together with its token in the keyring
+func (c *Config) RemoveClusterAuthInformation(key string) error {
+	delete(c.ClusterAuthInformation, key)
+	if err := keyring.Delete(monoctlService, key); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
 func (c *Config) SetClusterAuthInformation(clusterId, username, role, token string, expiry time.Time) {
 	c.ClusterAuthInformation[fmt.Sprintf("%s/%s/%s", clusterId, username, role)] = &AuthInformation{
 		Username: username,
@@ -130,6 +248,32 @@ func (c *Config) SetClusterAuthInformation(clusterId, username, role, token stri
 	}
 }
 
+// AddAPIToken adds the local record of an issued API token
+func (c *Config) AddAPIToken(apiToken *APITokenInformation) {
+	c.APITokens = append(c.APITokens, apiToken)
+}
+
+// GetAPIToken returns the local record of the API token with the given id or nil if unknown
+func (c *Config) GetAPIToken(id string) *APITokenInformation {
+	for _, apiToken := range c.APITokens {
+		if apiToken.Id == id {
+			return apiToken
+		}
+	}
+	return nil
+}
+
+// RemoveAPIToken removes the local record of the API token with the given id and reports if it was known
+func (c *Config) RemoveAPIToken(id string) bool {
+	for i, apiToken := range c.APITokens {
+		if apiToken.Id == id {
+			c.APITokens = append(c.APITokens[:i], c.APITokens[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Config) String() (string, error) {
 	bytes, err := yaml.Marshal(c)
 	if err != nil {
@@ -144,9 +288,9 @@ type AuthInformation struct {
 	Expiry   time.Time `yaml:"expiry,omitempty"`
 }
 
-// IsValid checks that Token is not empty and is not expired with an offset
-func (a *AuthInformation) IsValid() bool {
-	return a.HasToken() && !a.IsTokenExpired()
+// IsValid checks that Token is not empty and is not expired with the given offset
+func (a *AuthInformation) IsValid(offset time.Duration) bool {
+	return a.HasToken() && !a.IsTokenExpired(offset)
 }
 
 // IsValidExact checks that Token is not empty and is not expired
@@ -159,12 +303,111 @@ func (a *AuthInformation) HasToken() bool {
 	return a.Token != ""
 }
 
-// IsTokenExpired checks if the auth token is expired with an offset
-func (a *AuthInformation) IsTokenExpired() bool {
-	return a.Expiry.IsZero() || a.Expiry.Before(time.Now().UTC().Add(5*time.Minute)) // check if token is valid for at least five more minutes
+// IsTokenExpired checks if the auth token is expired within the given offset
+func (a *AuthInformation) IsTokenExpired(offset time.Duration) bool {
+	return a.Expiry.IsZero() || a.Expiry.Before(time.Now().UTC().Add(offset)) // check if token is valid for at least the offset
 }
 
 // IsTokenExpiredExact checks if the auth token is expired
 func (a *AuthInformation) IsTokenExpiredExact() bool {
 	return a.Expiry.IsZero() || a.Expiry.Before(time.Now().UTC().Add(1*time.Second)) // check if token is valid exactly
 }
+
+// APITokenInformation is the local record of an API token issued via monoctl. The token itself is never stored.
+// The m8 control plane neither lists nor revokes API tokens, a token stays valid until it expires.
+type APITokenInformation struct {
+	// Id is the unique id (jti) of the token
+	Id string `yaml:"id"`
+	// User is the name or UUID of the user the token has been issued for
+	User string `yaml:"user"`
+	// Scopes the token is valid for
+	Scopes []string `yaml:"scopes"`
+	// Validity is the requested validity period of the token
+	Validity time.Duration `yaml:"validity"`
+	// Created is the time the token has been issued at
+	Created time.Time `yaml:"created"`
+	// Expiry is the time the token expires at
+	Expiry time.Time `yaml:"expiry"`
+}
+
+// IsExpired checks if the API token is expired
+func (a *APITokenInformation) IsExpired() bool {
+	return a.Expiry.Before(time.Now().UTC())
+}
+
+// Status returns a human readable status of the API token
+func (a *APITokenInformation) Status() string {
+	if a.IsExpired() {
+		return "expired"
+	}
+	return "valid"
+}
+
+// KubeconfigSelection restricts the clusters and roles written to the kubeconfig by glob patterns
+type KubeconfigSelection struct {
+	// Clusters are patterns of names of the clusters to include
+	Clusters []string `yaml:"clusters,omitempty"`
+	// Roles are patterns of the cluster roles to include
+	Roles []string `yaml:"roles,omitempty"`
+	// Tenants are patterns of names of the tenants whose clusters to include
+	Tenants []string `yaml:"tenants,omitempty"`
+	// Exclude are patterns of cluster names or <cluster>/<role> combinations to exclude
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// IsEmpty checks if the selection does not restrict anything
+func (s *KubeconfigSelection) IsEmpty() bool {
+	return len(s.Clusters) == 0 && len(s.Roles) == 0 && len(s.Tenants) == 0 && len(s.Exclude) == 0
+}
+
+// Validate checks that all patterns are well-formed
+func (s *KubeconfigSelection) Validate() error {
+	for _, patterns := range [][]string{s.Clusters, s.Roles, s.Tenants, s.Exclude} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MatchesCluster checks if the cluster with the given name is included
+func (s *KubeconfigSelection) MatchesCluster(cluster string) bool {
+	return len(s.Clusters) == 0 || matchesAny(s.Clusters, cluster)
+}
+
+// MatchesRole checks if the given cluster role is included
+func (s *KubeconfigSelection) MatchesRole(role string) bool {
+	return len(s.Roles) == 0 || matchesAny(s.Roles, role)
+}
+
+// MatchesTenant checks if the clusters of the tenant with the given name are included
+func (s *KubeconfigSelection) MatchesTenant(tenant string) bool {
+	return len(s.Tenants) == 0 || matchesAny(s.Tenants, tenant)
+}
+
+// IsExcluded checks if the given cluster or the given role on it is excluded
+func (s *KubeconfigSelection) IsExcluded(cluster, role string) bool {
+	return matchesAny(s.Exclude, cluster) || matchesAny(s.Exclude, path.Join(cluster, role))
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// KubeconfigNaming contains Go templates for the names of generated kubeconfig entries.
+// The templates can refer to {{.Cluster}}, {{.Role}}, {{.User}} and {{.Tenant}}.
+type KubeconfigNaming struct {
+	// Context is the template for the names of contexts
+	Context string `yaml:"context,omitempty"`
+	// AuthInfo is the template for the names of users
+	AuthInfo string `yaml:"authInfo,omitempty"`
+	// Namespace is the template for the namespace of contexts
+	Namespace string `yaml:"namespace,omitempty"`
+}
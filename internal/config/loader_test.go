@@ -159,17 +159,127 @@ var _ = Describe("client config loader", func() {
 		conf.AuthInformation = &AuthInformation{}
 		Expect(conf.HasAuthInformation()).To(BeTrue())
 		Expect(conf.AuthInformation.HasToken()).To(BeFalse())
-		Expect(conf.AuthInformation.IsValid()).To(BeFalse())
+		Expect(conf.AuthInformation.IsValid(DefaultTokenExpiryOffset)).To(BeFalse())
 
 		conf.AuthInformation.Token = "test"
 		conf.AuthInformation.Expiry = time.Now().Add(1 * time.Hour)
 		Expect(conf.AuthInformation.HasToken()).To(BeTrue())
-		Expect(conf.AuthInformation.IsTokenExpired()).To(BeFalse())
-		Expect(conf.AuthInformation.IsValid()).To(BeTrue())
+		Expect(conf.AuthInformation.IsTokenExpired(DefaultTokenExpiryOffset)).To(BeFalse())
+		Expect(conf.AuthInformation.IsValid(DefaultTokenExpiryOffset)).To(BeTrue())
 
 		conf.AuthInformation.Expiry = time.Now().Add(-1 * time.Hour)
 		Expect(conf.AuthInformation.HasToken()).To(BeTrue())
-		Expect(conf.AuthInformation.IsTokenExpired()).To(BeTrue())
-		Expect(conf.AuthInformation.IsValid()).To(BeFalse())
+		Expect(conf.AuthInformation.IsTokenExpired(DefaultTokenExpiryOffset)).To(BeTrue())
+		Expect(conf.AuthInformation.IsValid(DefaultTokenExpiryOffset)).To(BeFalse())
+	})
+	It("uses the configured token expiry offset and warning", func() {
+		loader := NewLoader()
+		conf, err := loader.LoadFromBytes([]byte(fakeConfigData + "\ntokenExpiryOffset: 1m\ntokenExpiryWarning: 2h\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conf.GetTokenExpiryOffset()).To(Equal(1 * time.Minute))
+		Expect(conf.GetTokenExpiryWarning()).To(Equal(2 * time.Hour))
+
+		conf.AuthInformation = &AuthInformation{Token: "test", Expiry: time.Now().Add(3 * time.Minute)}
+		Expect(conf.AuthInformation.IsValid(DefaultTokenExpiryOffset)).To(BeFalse())
+		Expect(conf.AuthInformation.IsValid(conf.GetTokenExpiryOffset())).To(BeTrue())
+		Expect(conf.IsAuthInformationValid()).To(BeTrue())
+		Expect(conf.TokenExpiresSoon()).To(BeTrue())
+
+		conf.AuthInformation.Expiry = time.Now().Add(3 * time.Hour)
+		Expect(conf.TokenExpiresSoon()).To(BeFalse())
+
+		conf.TokenExpiryWarning = -1
+		conf.AuthInformation.Expiry = time.Now().Add(3 * time.Minute)
+		Expect(conf.TokenExpiresSoon()).To(BeFalse())
+	})
+	It("rejects a token expiry warning not longer than the offset", func() {
+		loader := NewLoader()
+		_, err := loader.LoadFromBytes([]byte(fakeConfigData + "\ntokenExpiryOffset: 10m\ntokenExpiryWarning: 10m\n"))
+		Expect(err).To(MatchError(ErrTokenExpiryWarning))
+
+		_, err = loader.LoadFromBytes([]byte(fakeConfigData + "\ntokenExpiryWarning: 1m\n"))
+		Expect(err).To(MatchError(ErrTokenExpiryWarning))
+	})
+	It("moves the default token expiry warning back behind a long offset", func() {
+		loader := NewLoader()
+		conf, err := loader.LoadFromBytes([]byte(fakeConfigData + "\ntokenExpiryOffset: 1h\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conf.GetTokenExpiryWarning()).To(Equal(time.Hour + DefaultTokenExpiryWarning - DefaultTokenExpiryOffset))
+
+		conf.AuthInformation = &AuthInformation{Token: "test", Expiry: time.Now().Add(70 * time.Minute)}
+		Expect(conf.IsAuthInformationValid()).To(BeTrue())
+		Expect(conf.TokenExpiresSoon()).To(BeTrue())
+	})
+	It("keeps tokens valid within the configured token expiry offset", func() {
+		tempFile, err := testutil_fs.NewTempFile([]byte(fakeConfigData + "\ntokenExpiryOffset: 1m\n"))
+		Expect(err).NotTo(HaveOccurred())
+		defer tempFile.Close()
+
+		loader := NewLoaderFromExplicitFile(tempFile.Path)
+		Expect(loader.LoadConfig()).To(Succeed())
+
+		conf := loader.GetConfig()
+		conf.SetClusterAuthInformation("cluster-id", "user", "default", "clustertoken", time.Now().UTC().Add(3*time.Minute))
+		Expect(loader.SaveConfig()).To(Succeed())
+
+		Expect(loader.LoadConfig()).To(Succeed())
+		Expect(loader.config.GetClusterAuthInformation("cluster-id", "user", "default")).ToNot(BeNil())
+	})
+	It("falls back to the default token expiry offset and warning", func() {
+		conf := NewConfig()
+		Expect(conf.GetTokenExpiryOffset()).To(Equal(DefaultTokenExpiryOffset))
+		Expect(conf.GetTokenExpiryWarning()).To(Equal(DefaultTokenExpiryWarning))
+		Expect(conf.TokenExpiresSoon()).To(BeFalse())
+	})
+	It("matches kubeconfig selections", func() {
+		selection := &KubeconfigSelection{}
+		Expect(selection.IsEmpty()).To(BeTrue())
+		Expect(selection.MatchesCluster("any")).To(BeTrue())
+		Expect(selection.MatchesRole("any")).To(BeTrue())
+		Expect(selection.MatchesTenant("any")).To(BeTrue())
+		Expect(selection.IsExcluded("any", "admin")).To(BeFalse())
+
+		selection = &KubeconfigSelection{
+			Clusters: []string{"prod-*", "staging"},
+			Roles:    []string{"admin"},
+			Tenants:  []string{"team-?"},
+			Exclude:  []string{"prod-legacy", "prod-eu/admin"},
+		}
+		Expect(selection.Validate()).To(Succeed())
+		Expect(selection.MatchesCluster("prod-us")).To(BeTrue())
+		Expect(selection.MatchesCluster("staging")).To(BeTrue())
+		Expect(selection.MatchesCluster("dev")).To(BeFalse())
+		Expect(selection.MatchesRole("admin")).To(BeTrue())
+		Expect(selection.MatchesRole("user")).To(BeFalse())
+		Expect(selection.MatchesTenant("team-a")).To(BeTrue())
+		Expect(selection.MatchesTenant("team-ab")).To(BeFalse())
+		Expect(selection.IsExcluded("prod-legacy", "admin")).To(BeTrue())
+		Expect(selection.IsExcluded("prod-eu", "admin")).To(BeTrue())
+		Expect(selection.IsExcluded("prod-eu", "user")).To(BeFalse())
+
+		selection.Clusters = []string{"[invalid"}
+		Expect(selection.Validate()).ToNot(Succeed())
+	})
+	It("falls back to the default kubeconfig naming templates", func() {
+		conf := NewConfig()
+		naming := conf.GetKubeconfigNaming()
+		Expect(naming.Context).To(Equal(DefaultContextNameTemplate))
+		Expect(naming.AuthInfo).To(Equal(DefaultAuthInfoNameTemplate))
+		Expect(naming.Namespace).To(Equal(DefaultNamespaceTemplate))
+
+		conf.KubeconfigNaming = &KubeconfigNaming{Namespace: "{{.Tenant}}-dev"}
+		naming = conf.GetKubeconfigNaming()
+		Expect(naming.Context).To(Equal(DefaultContextNameTemplate))
+		Expect(naming.Namespace).To(Equal("{{.Tenant}}-dev"))
+	})
+	It("parses cluster auth keys", func() {
+		clusterId, username, role, ok := ParseClusterAuthKey("some-id/jane.doe@monoskope.io/admin")
+		Expect(ok).To(BeTrue())
+		Expect(clusterId).To(Equal("some-id"))
+		Expect(username).To(Equal("jane.doe@monoskope.io"))
+		Expect(role).To(Equal("admin"))
+
+		_, _, _, ok = ParseClusterAuthKey("some-id/admin")
+		Expect(ok).To(BeFalse())
 	})
 })
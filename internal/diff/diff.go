@@ -0,0 +1,193 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContextLines is the number of unchanged lines shown around each change, as used by diff -u
+const DefaultContextLines = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// Unified returns the unified diff of the texts a and b, labeled with the given names.
+// An empty string is returned if both texts are equal.
+func Unified(nameA, nameB, a, b string, contextLines int) string {
+	if a == b {
+		return ""
+	}
+
+	ops := lineOps(splitLines(a), splitLines(b))
+
+	sb := new(strings.Builder)
+	fmt.Fprintf(sb, "--- %s\n", nameA)
+	fmt.Fprintf(sb, "+++ %s\n", nameB)
+
+	for _, h := range hunks(ops, contextLines) {
+		sb.WriteString(h)
+	}
+	return sb.String()
+}
+
+// splitLines splits the text into lines, keeping a missing trailing newline from producing an empty line
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// lineOps computes the edit script turning a into b based on the longest common subsequence of lines
+func lineOps(a, b []string) []op {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []op
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{opEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{opDelete, a[i]})
+			i++
+		default:
+			ops = append(ops, op{opInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{opDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{opInsert, b[j]})
+	}
+	return ops
+}
+
+// hunks groups the edit script into hunks with the given number of context lines
+func hunks(ops []op, contextLines int) []string {
+	var result []string
+
+	// line numbers in a and b before each op
+	posA := make([]int, len(ops)+1)
+	posB := make([]int, len(ops)+1)
+	for k, o := range ops {
+		posA[k+1], posB[k+1] = posA[k], posB[k]
+		if o.kind != opInsert {
+			posA[k+1]++
+		}
+		if o.kind != opDelete {
+			posB[k+1]++
+		}
+	}
+
+	k := 0
+	for k < len(ops) {
+		// find next change
+		for k < len(ops) && ops[k].kind == opEqual {
+			k++
+		}
+		if k == len(ops) {
+			break
+		}
+
+		start := k - contextLines
+		if start < 0 {
+			start = 0
+		}
+
+		// extend the hunk as long as changes are closer than twice the context
+		end := k
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+			if next == len(ops) || next-end > 2*contextLines {
+				break
+			}
+			end = next
+		}
+		stop := end + contextLines
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		sb := new(strings.Builder)
+		fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(posA[start], posA[stop]-posA[start]), hunkRange(posB[start], posB[stop]-posB[start]))
+		for _, o := range ops[start:stop] {
+			switch o.kind {
+			case opEqual:
+				sb.WriteString(" ")
+			case opDelete:
+				sb.WriteString("-")
+			case opInsert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(o.line)
+			sb.WriteString("\n")
+		}
+		result = append(result, sb.String())
+
+		k = stop
+	}
+
+	return result
+}
+
+// hunkRange formats the range of a hunk, line numbers are one based and empty ranges refer to the line before
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
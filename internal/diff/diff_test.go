@@ -0,0 +1,64 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Unified", func() {
+	It("returns nothing for equal texts", func() {
+		Expect(Unified("a", "b", "same\n", "same\n", DefaultContextLines)).To(BeEmpty())
+	})
+
+	It("diffs a changed line", func() {
+		a := "one\ntwo\nthree\n"
+		b := "one\n2\nthree\n"
+		Expect(Unified("a", "b", a, b, DefaultContextLines)).To(Equal(`--- a
++++ b
+@@ -1,3 +1,3 @@
+ one
+-two
++2
+ three
+`))
+	})
+
+	It("diffs against an empty text", func() {
+		Expect(Unified("a", "b", "", "one\ntwo\n", DefaultContextLines)).To(Equal(`--- a
++++ b
+@@ -0,0 +1,2 @@
++one
++two
+`))
+	})
+
+	It("splits distant changes into separate hunks", func() {
+		a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+		b := "x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n"
+		Expect(Unified("a", "b", a, b, 1)).To(Equal(`--- a
++++ b
+@@ -1,2 +1,2 @@
+-1
++x
+ 2
+@@ -9,2 +9,2 @@
+ 9
+-10
++y
+`))
+	})
+})
@@ -0,0 +1,37 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"fmt"
+
+	mjwt "github.com/finleap-connect/monoskope/pkg/jwt"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// ParseUnverified parses the given raw token and returns its claims WITHOUT verifying the signature.
+// Signatures are verified by the m8 control plane, this is only meant to inspect tokens issued by it.
+func ParseUnverified(rawToken string) (*mjwt.AuthToken, error) {
+	token, err := jwt.ParseSigned(rawToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	claims := new(mjwt.AuthToken)
+	if err := token.UnsafeClaimsWithoutVerification(claims); err != nil {
+		return nil, fmt.Errorf("failed to read token claims: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// DefaultBackupRetention is the default number of backups kept of a kubeconfig file
+	DefaultBackupRetention = 10
+	// backupDir is the directory next to the kubeconfig file the backups are stored in
+	backupDir = ".monoctl-backups"
+	// backupIdFormat is the format of the timestamps identifying backups
+	backupIdFormat = "20060102T150405.000Z"
+	backupSuffix   = ".yaml"
+)
+
+// Backup is a copy of a kubeconfig file taken before monoctl changed it
+type Backup struct {
+	// Id identifies the backup, it is the UTC timestamp of its creation
+	Id string
+	// Path is the path of the backup file
+	Path string
+	// Created is the time the backup has been taken
+	Created time.Time
+	// Size is the size of the backup in bytes
+	Size int64
+}
+
+// BackupDir returns the directory the backups of the kubeconfig file are stored in
+func (k *KubeConfig) BackupDir() string {
+	return filepath.Join(filepath.Dir(k.ConfigPath), backupDir, filepath.Base(k.ConfigPath))
+}
+
+// ListBackups returns the backups of the kubeconfig file, the newest first
+func (k *KubeConfig) ListBackups() ([]*Backup, error) {
+	entries, err := os.ReadDir(k.BackupDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []*Backup
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), backupSuffix) {
+			continue
+		}
+		id := strings.TrimSuffix(entry.Name(), backupSuffix)
+		created, err := time.Parse(backupIdFormat, id)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, &Backup{
+			Id:      id,
+			Path:    filepath.Join(k.BackupDir(), entry.Name()),
+			Created: created,
+			Size:    info.Size(),
+		})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Id > backups[j].Id
+	})
+	return backups, nil
+}
+
+// RestoreBackup replaces the kubeconfig file with the backup of the given id.
+// The current content is backed up before, so the restore can be undone.
+func (k *KubeConfig) RestoreBackup(id string) (*Backup, error) {
+	backups, err := k.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+	var backup *Backup
+	for _, b := range backups {
+		if b.Id == id {
+			backup = b
+			break
+		}
+	}
+	if backup == nil {
+		return nil, fmt.Errorf("backup '%s' of %s not found", id, k.ConfigPath)
+	}
+
+	// Make sure not to restore something kubectl can not read
+	if _, err := clientcmd.LoadFromFile(backup.Path); err != nil {
+		return nil, fmt.Errorf("backup '%s' is not a valid kubeconfig: %w", id, err)
+	}
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return backup, withLock(k.ConfigPath, func() error {
+		if err := k.backup(data); err != nil {
+			return err
+		}
+		return writeFile(k.ConfigPath, data)
+	})
+}
+
+// backup copies the kubeconfig file to the backup directory if its content differs from the data about to be written
+// and removes the backups exceeding the retention
+func (k *KubeConfig) backup(data []byte) error {
+	if k.BackupRetention < 0 {
+		return nil
+	}
+	current, err := os.ReadFile(k.ConfigPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(current, data) {
+		return nil
+	}
+
+	if err := os.MkdirAll(k.BackupDir(), 0700); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	backupPath := filepath.Join(k.BackupDir(), now.Format(backupIdFormat)+backupSuffix)
+	// Backups taken within the same millisecond must not overwrite each other
+	for _, err := os.Stat(backupPath); err == nil; _, err = os.Stat(backupPath) {
+		now = now.Add(time.Millisecond)
+		backupPath = filepath.Join(k.BackupDir(), now.Format(backupIdFormat)+backupSuffix)
+	}
+	if err := os.WriteFile(backupPath, current, 0600); err != nil {
+		return err
+	}
+	k.log.Info("Kubeconfig backed up.", "file", k.ConfigPath, "backup", backupPath)
+
+	return k.pruneBackups()
+}
+
+// pruneBackups removes the oldest backups exceeding the retention
+func (k *KubeConfig) pruneBackups() error {
+	backups, err := k.ListBackups()
+	if err != nil {
+		return err
+	}
+	for i := k.BackupRetention; i < len(backups); i++ {
+		if err := os.Remove(backups[i].Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+var _ = Describe("Internal/K8s/Backup", func() {
+	var (
+		kc     *KubeConfig
+		tmpDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "kubeconfig")
+		Expect(err).ToNot(HaveOccurred())
+
+		kc = NewKubeConfig()
+		kc.SetPath(filepath.Join(tmpDir, "config"))
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	storeWithContext := func(name string) {
+		conf := api.NewConfig()
+		conf.Contexts[name] = api.NewContext()
+		conf.CurrentContext = name
+		Expect(kc.StoreConfig(conf)).To(Succeed())
+	}
+
+	It("backs up changed kubeconfigs and keeps only the configured number of backups", func() {
+		kc.BackupRetention = 2
+		storeWithContext("first")
+		backups, err := kc.ListBackups()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backups).To(BeEmpty())
+
+		storeWithContext("second")
+		storeWithContext("second")
+		backups, err = kc.ListBackups()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backups).To(HaveLen(1))
+
+		storeWithContext("third")
+		storeWithContext("fourth")
+		backups, err = kc.ListBackups()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backups).To(HaveLen(2))
+		Expect(backups[0].Created).To(BeTemporally(">=", backups[1].Created))
+
+		restored, err := kc.RestoreBackup(backups[1].Id)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restored.Id).To(Equal(backups[1].Id))
+		conf, err := kc.LoadConfig()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conf.CurrentContext).To(Equal("second"))
+
+		_, err = kc.RestoreBackup("unknown")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("waits for the lock of the kubeconfig", func() {
+		lockFile := kc.ConfigPath + lockSuffix
+		Expect(os.WriteFile(lockFile, nil, 0600)).To(Succeed())
+
+		defer func(timeout time.Duration) { lockTimeout = timeout }(lockTimeout)
+		lockTimeout = 200 * time.Millisecond
+		conf := api.NewConfig()
+		err := kc.StoreConfig(conf)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("is locked by another process"))
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			os.Remove(lockFile)
+		}()
+		Expect(kc.StoreConfig(conf)).To(Succeed())
+		Expect(lockFile).ToNot(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,277 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// caDiscoveryTimeout is the time to wait for the API server while discovering its CA
+	caDiscoveryTimeout = 10 * time.Second
+	// clusterInfoPath is the path of the ConfigMap kubeadm and most distributions publish the CA of the cluster in, readable anonymously
+	clusterInfoPath = "/api/v1/namespaces/kube-public/configmaps/cluster-info"
+	// clusterInfoKubeconfigKey is the key of the kubeconfig within the cluster-info ConfigMap
+	clusterInfoKubeconfigKey = "kubeconfig"
+)
+
+// DiscoverCACertificates connects to the API server and returns the CA certificates of the chain it presents.
+// Most API servers present their serving certificate only, in which case the CA is read from the cluster-info
+// ConfigMap and has to have issued the serving certificate.
+// Neither is verified otherwise, the certificates have to be confirmed by the user before trusting them.
+func DiscoverCACertificates(ctx context.Context, apiServerAddress string) ([]*x509.Certificate, error) {
+	u, err := url.Parse(apiServerAddress)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid API server address '%s', must be https", apiServerAddress)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, caDiscoveryTimeout)
+	defer cancel()
+	tlsConfig := &tls.Config{
+		ServerName: u.Hostname(),
+		// The chain presented is what we are looking for, trust is established by the user confirming it
+		InsecureSkipVerify: true,
+	}
+	dialer := &tls.Dialer{Config: tlsConfig}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return nil, fmt.Errorf("TLS handshake with %s failed: %w", apiServerAddress, err)
+	}
+	presented := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	conn.Close()
+
+	var caCerts []*x509.Certificate
+	for _, cert := range presented {
+		if cert.IsCA {
+			caCerts = append(caCerts, cert)
+		}
+	}
+	if len(caCerts) > 0 {
+		return caCerts, nil
+	}
+
+	caCerts, err = discoverClusterInfoCA(ctx, u, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%s presented no CA certificate and reading the cluster-info ConfigMap failed, the CA bundle has to be given as file: %w", apiServerAddress, err)
+	}
+	if err := verifyIssuedBy(presented, caCerts); err != nil {
+		return nil, fmt.Errorf("the CA of the cluster-info ConfigMap did not issue the certificate presented by %s: %w", apiServerAddress, err)
+	}
+	return caCerts, nil
+}
+
+// discoverClusterInfoCA returns the CA certificates of the kubeconfig published in the cluster-info ConfigMap
+func discoverClusterInfoCA(ctx context.Context, apiServer *url.URL, tlsConfig *tls.Config) ([]*x509.Certificate, error) {
+	clusterInfoURL := &url.URL{Scheme: apiServer.Scheme, Host: apiServer.Host, Path: path.Join(apiServer.Path, clusterInfoPath)}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, clusterInfoURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading %s returned %s", clusterInfoPath, resp.Status)
+	}
+
+	configMap := new(corev1.ConfigMap)
+	if err := json.NewDecoder(resp.Body).Decode(configMap); err != nil {
+		return nil, err
+	}
+	kubeConfig, err := clientcmd.Load([]byte(configMap.Data[clusterInfoKubeconfigKey]))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(kubeConfig.Clusters))
+	for name := range kubeConfig.Clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if caData := kubeConfig.Clusters[name].CertificateAuthorityData; len(caData) > 0 {
+			return ParseCABundle(caData)
+		}
+	}
+	return nil, errors.New("cluster-info contains no CA certificate")
+}
+
+// verifyIssuedBy checks that the first certificate of the chain has been issued by one of the CA certificates
+func verifyIssuedBy(chain []*x509.Certificate, caCerts []*x509.Certificate) error {
+	if len(chain) == 0 {
+		return errors.New("no certificate presented")
+	}
+	roots := x509.NewCertPool()
+	for _, cert := range caCerts {
+		roots.AddCert(cert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+// CertificateFingerprint returns the SHA-256 fingerprint of the certificate in the format openssl prints it
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	encoded := strings.ToUpper(hex.EncodeToString(sum[:]))
+	parts := make([]string, 0, len(sum))
+	for i := 0; i < len(encoded); i += 2 {
+		parts = append(parts, encoded[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
+
+// normalizeFingerprint strips separators and case from a fingerprint for comparison
+func normalizeFingerprint(fingerprint string) string {
+	fingerprint = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(fingerprint)), "sha256:")
+	return strings.NewReplacer(":", "", " ", "").Replace(fingerprint)
+}
+
+// VerifyCAFingerprint checks that one of the certificates has the given SHA-256 fingerprint
+func VerifyCAFingerprint(certs []*x509.Certificate, fingerprint string) error {
+	expected := normalizeFingerprint(fingerprint)
+	for _, cert := range certs {
+		if normalizeFingerprint(CertificateFingerprint(cert)) == expected {
+			return nil
+		}
+	}
+	return fmt.Errorf("no CA certificate with fingerprint %s presented", fingerprint)
+}
+
+// PrintCertificates writes subject, issuer, fingerprint and expiry of the certificates to out
+func PrintCertificates(out io.Writer, certs []*x509.Certificate) {
+	for i, cert := range certs {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "Subject:     %s\n", cert.Subject)
+		fmt.Fprintf(out, "Issuer:      %s\n", cert.Issuer)
+		fmt.Fprintf(out, "SHA-256:     %s\n", CertificateFingerprint(cert))
+		fmt.Fprintf(out, "Expires:     %s\n", cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+}
+
+// EncodeCertificates returns the certificates as PEM bundle
+func EncodeCertificates(certs []*x509.Certificate) []byte {
+	buf := new(bytes.Buffer)
+	for _, cert := range certs {
+		_ = pem.Encode(buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return bytes.TrimSpace(buf.Bytes())
+}
+
+// DiscoverCABundle discovers the CA certificates of the API server and returns them as PEM bundle once trusted.
+// They are trusted if one has the given fingerprint or, if no fingerprint is given, the user confirms them.
+// Without fingerprint and confirm func discovering fails, as nobody can confirm the certificates.
+func DiscoverCABundle(ctx context.Context, apiServerAddress, fingerprint string, out io.Writer, confirm func(msg string) bool) ([]byte, error) {
+	certs, err := DiscoverCACertificates(ctx, apiServerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(out, "CA certificates of %s:\n", apiServerAddress)
+	PrintCertificates(out, certs)
+	fmt.Fprintln(out)
+
+	switch {
+	case fingerprint != "":
+		if err := VerifyCAFingerprint(certs, fingerprint); err != nil {
+			return nil, err
+		}
+	case confirm == nil:
+		return nil, errors.New("the CA fingerprint must be given to trust the discovered CA certificates non-interactively")
+	case !confirm("Do you trust these CA certificates"):
+		return nil, errors.New("discovered CA certificates not trusted")
+	}
+	return EncodeCertificates(certs), nil
+}
+
+// ParseCABundle parses the PEM encoded CA bundle, every block of it must be a CA certificate
+func ParseCABundle(bundle []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := bytes.TrimSpace(bundle)
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("CA bundle contains data which is not PEM encoded after %d certificates", len(certs))
+		}
+		rest = bytes.TrimSpace(rest)
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("CA bundle contains a %s, only certificates are allowed", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("CA bundle contains an invalid certificate: %w", err)
+		}
+		if !cert.IsCA {
+			return nil, fmt.Errorf("certificate '%s' of the CA bundle is no CA certificate", cert.Subject)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("CA bundle contains no certificate")
+	}
+	return certs, nil
+}
+
+// CertificateValidityWarnings returns a warning for every certificate not valid at the given time
+func CertificateValidityWarnings(certs []*x509.Certificate, now time.Time) []string {
+	var warnings []string
+	for _, cert := range certs {
+		if now.After(cert.NotAfter) {
+			warnings = append(warnings, fmt.Sprintf("CA certificate '%s' expired at %s", cert.Subject, cert.NotAfter.UTC().Format(time.RFC3339)))
+		} else if now.Before(cert.NotBefore) {
+			warnings = append(warnings, fmt.Sprintf("CA certificate '%s' is not valid before %s", cert.Subject, cert.NotBefore.UTC().Format(time.RFC3339)))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,259 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+var _ = Describe("CA discovery", func() {
+	var (
+		ctx    = context.Background()
+		server *httptest.Server
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("discovers the CA certificates presented by the server", func() {
+		certs, err := DiscoverCACertificates(ctx, server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certs).To(HaveLen(1))
+		Expect(certs[0].Equal(server.Certificate())).To(BeTrue())
+	})
+
+	It("trusts the certificates with the given fingerprint", func() {
+		fingerprint := CertificateFingerprint(server.Certificate())
+		out := new(bytes.Buffer)
+
+		bundle, err := DiscoverCABundle(ctx, server.URL, fingerprint, out, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.String()).To(ContainSubstring("SHA-256:     " + fingerprint))
+		Expect(out.String()).To(ContainSubstring("Subject:     O=Acme Co"))
+
+		block, rest := pem.Decode(bundle)
+		Expect(block).ToNot(BeNil())
+		Expect(rest).To(BeEmpty())
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cert.Equal(server.Certificate())).To(BeTrue())
+
+		_, err = DiscoverCABundle(ctx, server.URL, "00:11:22", out, nil)
+		Expect(err).To(MatchError("no CA certificate with fingerprint 00:11:22 presented"))
+	})
+
+	It("asks the user to confirm the certificates without fingerprint", func() {
+		out := new(bytes.Buffer)
+		var asked bool
+		_, err := DiscoverCABundle(ctx, server.URL, "", out, func(msg string) bool {
+			asked = true
+			return false
+		})
+		Expect(asked).To(BeTrue())
+		Expect(err).To(MatchError("discovered CA certificates not trusted"))
+
+		_, err = DiscoverCABundle(ctx, server.URL, "", out, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("server presenting its serving certificate only", func() {
+		var (
+			caPEM    []byte
+			caCert   *x509.Certificate
+			leafCert tls.Certificate
+		)
+
+		BeforeEach(func() {
+			caCert, caPEM, leafCert = newTestServingCertificate()
+		})
+
+		// newLeafServer returns a server presenting the leaf certificate and serving the kubeconfig as cluster-info
+		newLeafServer := func(clusterInfoCA []byte) *httptest.Server {
+			kubeConfig := api.NewConfig()
+			kubeConfig.Clusters[""] = &api.Cluster{Server: "https://127.0.0.1", CertificateAuthorityData: clusterInfoCA}
+			kubeConfigData, err := clientcmd.Write(*kubeConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			leafServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != clusterInfoPath {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				Expect(json.NewEncoder(w).Encode(&corev1.ConfigMap{
+					Data: map[string]string{clusterInfoKubeconfigKey: string(kubeConfigData)},
+				})).To(Succeed())
+			}))
+			leafServer.TLS = &tls.Config{Certificates: []tls.Certificate{leafCert}}
+			leafServer.StartTLS()
+			return leafServer
+		}
+
+		It("discovers the CA from the cluster-info ConfigMap", func() {
+			leafServer := newLeafServer(caPEM)
+			defer leafServer.Close()
+
+			certs, err := DiscoverCACertificates(ctx, leafServer.URL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(certs).To(HaveLen(1))
+			Expect(certs[0].Equal(caCert)).To(BeTrue())
+
+			bundle, err := DiscoverCABundle(ctx, leafServer.URL, CertificateFingerprint(caCert), new(bytes.Buffer), nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bundle).To(Equal(bytes.TrimSpace(caPEM)))
+		})
+
+		It("rejects a cluster-info CA which did not issue the serving certificate", func() {
+			otherCA, _, _ := newTestServingCertificate()
+			leafServer := newLeafServer(EncodeCertificates([]*x509.Certificate{otherCA}))
+			defer leafServer.Close()
+
+			_, err := DiscoverCACertificates(ctx, leafServer.URL)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("did not issue the certificate presented"))
+		})
+
+		It("fails if the cluster-info ConfigMap can not be read", func() {
+			leafServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			}))
+			leafServer.TLS = &tls.Config{Certificates: []tls.Certificate{leafCert}}
+			leafServer.StartTLS()
+			defer leafServer.Close()
+
+			_, err := DiscoverCACertificates(ctx, leafServer.URL)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the CA bundle has to be given as file"))
+		})
+	})
+})
+
+// newTestServingCertificate returns a CA, PEM encoded as well, and a serving certificate for 127.0.0.1 issued by it
+func newTestServingCertificate() (*x509.Certificate, []byte, tls.Certificate) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	Expect(err).ToNot(HaveOccurred())
+	caCert, err := x509.ParseCertificate(caDER)
+	Expect(err).ToNot(HaveOccurred())
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	leafTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "kube-apiserver"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, caCert, &leafKey.PublicKey, caKey)
+	Expect(err).ToNot(HaveOccurred())
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	return caCert, caPEM, tls.Certificate{Certificate: [][]byte{leafDER}, PrivateKey: leafKey}
+}
+
+// newTestCertificate returns a PEM encoded self-signed certificate valid in the given period
+func newTestCertificate(commonName string, isCA bool, notBefore, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+var _ = Describe("CA bundle validation", func() {
+	now := time.Now()
+
+	It("parses bundles of CA certificates", func() {
+		bundle := append(newTestCertificate("first-ca", true, now.Add(-time.Hour), now.Add(time.Hour)), '\n')
+		bundle = append(bundle, newTestCertificate("second-ca", true, now.Add(-time.Hour), now.Add(time.Hour))...)
+
+		certs, err := ParseCABundle(bundle)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certs).To(HaveLen(2))
+		Expect(certs[1].Subject.CommonName).To(Equal("second-ca"))
+		Expect(CertificateValidityWarnings(certs, now)).To(BeEmpty())
+	})
+
+	It("rejects anything but CA certificates", func() {
+		_, err := ParseCABundle([]byte("This should be a certificate"))
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParseCABundle(nil)
+		Expect(err).To(MatchError("CA bundle contains no certificate"))
+
+		_, err = ParseCABundle(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("key")}))
+		Expect(err).To(MatchError("CA bundle contains a EC PRIVATE KEY, only certificates are allowed"))
+
+		_, err = ParseCABundle(newTestCertificate("leaf", false, now.Add(-time.Hour), now.Add(time.Hour)))
+		Expect(err).To(MatchError("certificate 'CN=leaf' of the CA bundle is no CA certificate"))
+	})
+
+	It("warns about certificates not valid now", func() {
+		bundle := append(newTestCertificate("expired-ca", true, now.Add(-2*time.Hour), now.Add(-time.Hour)), newTestCertificate("future-ca", true, now.Add(time.Hour), now.Add(2*time.Hour))...)
+
+		certs, err := ParseCABundle(bundle)
+		Expect(err).ToNot(HaveOccurred())
+		warnings := CertificateValidityWarnings(certs, now)
+		Expect(warnings).To(HaveLen(2))
+		Expect(warnings[0]).To(HavePrefix("CA certificate 'CN=expired-ca' expired at"))
+		Expect(warnings[1]).To(HavePrefix("CA certificate 'CN=future-ca' is not valid before"))
+	})
+})
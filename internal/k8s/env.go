@@ -0,0 +1,113 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Shell is a shell for which the export of environment variables can be rendered
+type Shell string
+
+const (
+	ShellBash       Shell = "bash"
+	ShellZsh        Shell = "zsh"
+	ShellFish       Shell = "fish"
+	ShellPowerShell Shell = "powershell"
+)
+
+// Shells are the supported shells
+var Shells = []Shell{ShellBash, ShellZsh, ShellFish, ShellPowerShell}
+
+// ParseShell returns the shell of the given name
+func ParseShell(name string) (Shell, error) {
+	name = strings.ToLower(name)
+	if name == "pwsh" {
+		return ShellPowerShell, nil
+	}
+	for _, shell := range Shells {
+		if string(shell) == name {
+			return shell, nil
+		}
+	}
+	return "", fmt.Errorf("shell '%s' is not supported, supported shells: %v", name, Shells)
+}
+
+// DetectShell returns the shell of the current user based on $SHELL, falling back to bash or PowerShell on windows
+func DetectShell() Shell {
+	if shell, err := ParseShell(filepath.Base(os.Getenv("SHELL"))); err == nil {
+		return shell
+	}
+	if runtime.GOOS == "windows" {
+		return ShellPowerShell
+	}
+	return ShellBash
+}
+
+// KubeConfigEnv returns the value of KUBECONFIG which adds the given file to the ones currently used by kubectl
+func KubeConfigEnv(file string) (string, error) {
+	file, err := ExpandPath(file)
+	if err != nil {
+		return "", err
+	}
+
+	current := filepath.SplitList(os.Getenv(kubeConfigEnvVar))
+	if len(current) == 0 {
+		defaultPath, err := ExpandPath(DefaultKubeConfigPath)
+		if err != nil {
+			return "", err
+		}
+		current = []string{defaultPath}
+	}
+
+	files := []string{file}
+	for _, f := range current {
+		if f != "" && f != file {
+			files = append(files, f)
+		}
+	}
+	return strings.Join(files, string(os.PathListSeparator)), nil
+}
+
+// ExportEnv returns the command setting the environment variable in the shell
+func ExportEnv(shell Shell, name, value string) string {
+	switch shell {
+	case ShellFish:
+		value = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+		return fmt.Sprintf("set -gx %s '%s'", name, value)
+	case ShellPowerShell:
+		value = strings.ReplaceAll(value, `'`, `''`)
+		return fmt.Sprintf("$Env:%s = '%s'", name, value)
+	default:
+		value = strings.ReplaceAll(value, `'`, `'\''`)
+		return fmt.Sprintf("export %s='%s'", name, value)
+	}
+}
+
+// EvalHint returns how to apply the output of the command in the shell
+func EvalHint(shell Shell, command string) string {
+	switch shell {
+	case ShellFish:
+		return fmt.Sprintf("%s | source", command)
+	case ShellPowerShell:
+		return fmt.Sprintf("%s | Invoke-Expression", command)
+	default:
+		return fmt.Sprintf("eval \"$(%s)\"", command)
+	}
+}
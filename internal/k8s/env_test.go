@@ -0,0 +1,57 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Internal/K8s/Env", func() {
+	AfterEach(func() {
+		os.Setenv(kubeConfigEnvVar, "")
+	})
+
+	It("adds the file to the kubeconfig files in use", func() {
+		sep := string(os.PathListSeparator)
+		Expect(os.Setenv(kubeConfigEnvVar, strings.Join([]string{"/a/config", "/m8/monoskope.yaml", "/b/config"}, sep))).To(Succeed())
+		value, err := KubeConfigEnv("/m8/monoskope.yaml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal(strings.Join([]string{"/m8/monoskope.yaml", "/a/config", "/b/config"}, sep)))
+
+		Expect(os.Setenv(kubeConfigEnvVar, "")).To(Succeed())
+		value, err = KubeConfigEnv("/m8/monoskope.yaml")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filepath.SplitList(value)).To(HaveLen(2))
+		Expect(filepath.SplitList(value)[1]).To(HaveSuffix(filepath.Join(".kube", "config")))
+	})
+
+	It("renders the export for all shells", func() {
+		Expect(ExportEnv(ShellBash, "KUBECONFIG", "/it's/config")).To(Equal(`export KUBECONFIG='/it'\''s/config'`))
+		Expect(ExportEnv(ShellZsh, "KUBECONFIG", "/config")).To(Equal(`export KUBECONFIG='/config'`))
+		Expect(ExportEnv(ShellFish, "KUBECONFIG", "/it's/config")).To(Equal(`set -gx KUBECONFIG '/it\'s/config'`))
+		Expect(ExportEnv(ShellPowerShell, "KUBECONFIG", "/it's/config")).To(Equal(`$Env:KUBECONFIG = '/it''s/config'`))
+
+		shell, err := ParseShell("pwsh")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(shell).To(Equal(ShellPowerShell))
+		_, err = ParseShell("tcsh")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,115 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// ExecInfoEnvVar is the environment variable kubectl passes the ExecCredential to credential plugins in
+	ExecInfoEnvVar = "KUBERNETES_EXEC_INFO"
+	// ExecClusterExtension is the name of the cluster extension kubectl passes to credential plugins if provideClusterInfo is set
+	ExecClusterExtension = "client.authentication.k8s.io/exec"
+
+	// ExecCredentialV1 is the version v1 of the ExecCredential API
+	ExecCredentialV1 = "client.authentication.k8s.io/v1"
+	// ExecCredentialV1beta1 is the version v1beta1 of the ExecCredential API
+	ExecCredentialV1beta1 = "client.authentication.k8s.io/v1beta1"
+)
+
+// ExecClusterConfig is the configuration of monoctl as credential plugin for a cluster
+type ExecClusterConfig struct {
+	// ClusterId is the id of the cluster in monoskope
+	ClusterId string `json:"clusterId,omitempty"`
+}
+
+// ExecInfo is the ExecCredential kubectl passes to credential plugins
+type ExecInfo struct {
+	APIVersion string `json:"apiVersion"`
+	Spec       struct {
+		Cluster *struct {
+			Server string          `json:"server"`
+			Config json.RawMessage `json:"config,omitempty"`
+		} `json:"cluster,omitempty"`
+		Interactive bool `json:"interactive"`
+	} `json:"spec"`
+}
+
+// GetExecInfo returns the ExecCredential passed by kubectl or nil if monoctl has not been called by kubectl
+func GetExecInfo() (*ExecInfo, error) {
+	value := os.Getenv(ExecInfoEnvVar)
+	if value == "" {
+		return nil, nil
+	}
+	return ParseExecInfo(value)
+}
+
+// ParseExecInfo parses the ExecCredential passed by kubectl
+func ParseExecInfo(value string) (*ExecInfo, error) {
+	execInfo := &ExecInfo{}
+	if err := json.Unmarshal([]byte(value), execInfo); err != nil {
+		return nil, fmt.Errorf("failed parsing %s: %w", ExecInfoEnvVar, err)
+	}
+	if err := ValidateExecCredentialAPIVersion(execInfo.APIVersion); err != nil {
+		return nil, err
+	}
+	return execInfo, nil
+}
+
+// Server returns the address of the API server of the cluster kubectl is connecting to
+func (e *ExecInfo) Server() string {
+	if e.Spec.Cluster == nil {
+		return ""
+	}
+	return e.Spec.Cluster.Server
+}
+
+// ClusterConfig returns the configuration of monoctl written to the cluster extension
+func (e *ExecInfo) ClusterConfig() *ExecClusterConfig {
+	clusterConfig := &ExecClusterConfig{}
+	if e.Spec.Cluster == nil || len(e.Spec.Cluster.Config) == 0 {
+		return clusterConfig
+	}
+	if err := json.Unmarshal(e.Spec.Cluster.Config, clusterConfig); err != nil {
+		return &ExecClusterConfig{}
+	}
+	return clusterConfig
+}
+
+// ValidateExecCredentialAPIVersion checks if the version of the ExecCredential API is supported
+func ValidateExecCredentialAPIVersion(apiVersion string) error {
+	if apiVersion != ExecCredentialV1 && apiVersion != ExecCredentialV1beta1 {
+		return fmt.Errorf("ExecCredential version '%s' is not supported, supported versions: %s, %s", apiVersion, ExecCredentialV1, ExecCredentialV1beta1)
+	}
+	return nil
+}
+
+// SetExecClusterConfig sets the configuration kubectl passes to monoctl as credential plugin of a cluster and returns the updated extensions
+func SetExecClusterConfig(extensions map[string]runtime.Object, clusterConfig *ExecClusterConfig) map[string]runtime.Object {
+	if extensions == nil {
+		extensions = make(map[string]runtime.Object)
+	}
+	raw, err := json.Marshal(clusterConfig)
+	if err != nil {
+		raw = []byte("{}")
+	}
+	extensions[ExecClusterExtension] = &runtime.Unknown{Raw: raw, ContentType: runtime.ContentTypeJSON}
+	return extensions
+}
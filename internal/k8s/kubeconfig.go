@@ -28,27 +28,64 @@ import (
 	"k8s.io/client-go/util/homedir"
 )
 
-const kubeConfigEnvVar = "KUBECONFIG"
+const (
+	kubeConfigEnvVar = "KUBECONFIG"
+	// StandaloneKubeConfigPath is the path of the kubeconfig file which contains only the entries managed by monoctl
+	StandaloneKubeConfigPath = "~/.kube/monoskope.yaml"
+	// DefaultKubeConfigPath is the path of the default kubeconfig file of kubectl
+	DefaultKubeConfigPath = "~/.kube/config"
+)
 
 type KubeConfig struct {
 	log        logger.Logger
 	ConfigPath string
+	// BackupRetention is the number of backups kept of the kubeconfig file, backups are disabled if negative
+	BackupRetention int
+	// isInteractive checks if the user can be asked to choose a file
+	isInteractive func() bool
 }
 
 func NewKubeConfig() *KubeConfig {
 	return &KubeConfig{
-		log: logger.WithName("KubeConfig"),
+		log:             logger.WithName("KubeConfig"),
+		BackupRetention: DefaultBackupRetention,
+		isInteractive:   prompt.IsTerminal,
 	}
 }
 
 func (k *KubeConfig) LoadConfig() (*api.Config, error) {
+	if err := k.ResolvePath(); err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(k.ConfigPath); err != nil {
+		if os.IsNotExist(err) {
+			return api.NewConfig(), nil
+		} else {
+			return nil, err
+		}
+	}
+
+	config, err := clientcmd.LoadFromFile(k.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ResolvePath determines the kubeconfig file to use from the configured path, $KUBECONFIG or the default of kubectl
+func (k *KubeConfig) ResolvePath() error {
 	if k.ConfigPath == "" {
 		k.ConfigPath = os.Getenv(kubeConfigEnvVar)
 	}
 
 	if k.ConfigPath != "" {
 		fileList := filepath.SplitList(k.ConfigPath)
-		if len(fileList) > 1 {
+		if len(fileList) > 1 && !k.isInteractive() {
+			// Like kubectl new entries are written to the first file of the list
+			k.ConfigPath = fileList[0]
+			k.log.Info("Multiple kubeconfig files specified and no terminal to choose one, using the first.", "file", k.ConfigPath)
+		} else if len(fileList) > 1 {
 			var err error
 			_, k.ConfigPath, err = prompt.SelectWithAdd(
 				"please specify a config file",
@@ -56,7 +93,7 @@ func (k *KubeConfig) LoadConfig() (*api.Config, error) {
 				fileList,
 			)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 	}
@@ -65,32 +102,49 @@ func (k *KubeConfig) LoadConfig() (*api.Config, error) {
 		k.ConfigPath = path.Join("~", ".kube", "config")
 	}
 
-	k.ConfigPath = os.ExpandEnv(k.ConfigPath)
-	if strings.HasPrefix(k.ConfigPath, "~/") {
+	var err error
+	k.ConfigPath, err = ExpandPath(k.ConfigPath)
+	return err
+}
+
+// ExpandPath expands environment variables and the home directory shorthand (~) in the path
+func ExpandPath(p string) (string, error) {
+	p = os.ExpandEnv(p)
+	if strings.HasPrefix(p, "~/") {
 		homeDir := homedir.HomeDir()
 		if homeDir == "" {
-			return nil, errors.New("couldn't determine home directory")
-		}
-		k.ConfigPath = filepath.Join(homeDir, k.ConfigPath[2:])
-	}
-
-	if _, err := os.Stat(k.ConfigPath); err != nil {
-		if os.IsNotExist(err) {
-			return api.NewConfig(), nil
-		} else {
-			return nil, err
+			return "", errors.New("couldn't determine home directory")
 		}
+		p = filepath.Join(homeDir, p[2:])
 	}
+	return p, nil
+}
 
-	config, err := clientcmd.LoadFromFile(k.ConfigPath)
+// StoreConfig writes the kubeconfig while holding the lock of the file.
+// The previous content is backed up before.
+func (k *KubeConfig) StoreConfig(conf *api.Config) error {
+	data, err := clientcmd.Write(*conf)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return config, nil
+
+	return withLock(k.ConfigPath, func() error {
+		if err := k.backup(data); err != nil {
+			return err
+		}
+		return writeFile(k.ConfigPath, data)
+	})
 }
 
-func (k *KubeConfig) StoreConfig(conf *api.Config) error {
-	return clientcmd.WriteToFile(*conf, k.ConfigPath)
+// writeFile writes the kubeconfig data like clientcmd.WriteToFile
+func writeFile(filename string, data []byte) error {
+	dir := filepath.Dir(filename)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filename, data, 0600)
 }
 
 func (k *KubeConfig) SetPath(path string) {
@@ -82,9 +82,19 @@ var _ = Describe("Internal/K8s/KubeConfig", func() {
 		It("will ask when multiple files are specified in $"+kubeConfigEnvVar, func() {
 			err := os.Setenv(kubeConfigEnvVar, tmpFile.Name()+":another/file")
 			Expect(err).ToNot(HaveOccurred())
+			kc.isInteractive = func() bool { return true }
 			_, err = kc.LoadConfig()
 			Expect(err.Error()).To(Equal("^D")) // asked but no user input
 		})
+
+		It("will use the first of multiple files in $"+kubeConfigEnvVar+" without terminal", func() {
+			err := os.Setenv(kubeConfigEnvVar, tmpFile.Name()+":another/file")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.Setenv(kubeConfigEnvVar, "")
+			kc.isInteractive = func() bool { return false }
+			CheckConfig()
+			Expect(kc.ConfigPath).To(Equal(tmpFile.Name()))
+		})
 	})
 	It("can store config", func() {
 		conf, err := kc.LoadConfig()
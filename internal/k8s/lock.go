@@ -0,0 +1,67 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const lockSuffix = ".lock"
+
+var (
+	// lockTimeout is how long to wait for the lock of a kubeconfig file held by another process
+	lockTimeout = 5 * time.Second
+	// lockRetryInterval is the interval in which acquiring the lock is retried
+	lockRetryInterval = 100 * time.Millisecond
+)
+
+// withLock calls f while holding the lock of the kubeconfig file.
+// Like kubectl the lock is a file next to the kubeconfig with the suffix .lock which is created exclusively.
+func withLock(filename string, f func() error) error {
+	lockFile := filename + lockSuffix
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err := createLockFile(lockFile)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("kubeconfig %s is locked by another process, remove %s if no other process is running", filename, lockFile)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+	defer os.Remove(lockFile)
+
+	return f()
+}
+
+// createLockFile creates the lock file, failing if it exists already
+func createLockFile(lockFile string) error {
+	if err := os.MkdirAll(filepath.Dir(lockFile), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL, 0)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
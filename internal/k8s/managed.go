@@ -0,0 +1,64 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ManagedExtension is the name of the extension marking kubeconfig entries managed by monoctl
+const ManagedExtension = "monoskope.io/monoctl"
+
+// Managed is the content of the extension marking kubeconfig entries managed by monoctl
+type Managed struct {
+	// Namespace is the namespace monoctl has set on a context. If the namespace of the context differs, it has been changed by the user.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// GetManaged returns the marker of a kubeconfig entry managed by monoctl
+func GetManaged(extensions map[string]runtime.Object) (*Managed, bool) {
+	obj, ok := extensions[ManagedExtension]
+	if !ok {
+		return nil, false
+	}
+	managed := &Managed{}
+	if unknown, ok := obj.(*runtime.Unknown); ok && len(unknown.Raw) > 0 {
+		if err := json.Unmarshal(unknown.Raw, managed); err != nil {
+			return &Managed{}, true
+		}
+	}
+	return managed, true
+}
+
+// IsManaged checks if a kubeconfig entry is marked as managed by monoctl
+func IsManaged(extensions map[string]runtime.Object) bool {
+	_, ok := extensions[ManagedExtension]
+	return ok
+}
+
+// SetManaged marks a kubeconfig entry as managed by monoctl and returns the updated extensions
+func SetManaged(extensions map[string]runtime.Object, managed *Managed) map[string]runtime.Object {
+	if extensions == nil {
+		extensions = make(map[string]runtime.Object)
+	}
+	raw, err := json.Marshal(managed)
+	if err != nil {
+		raw = []byte("{}")
+	}
+	extensions[ManagedExtension] = &runtime.Unknown{Raw: raw, ContentType: runtime.ContentTypeJSON}
+	return extensions
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+var _ = Describe("Internal/K8s/Managed", func() {
+	It("keeps the marker of managed entries when writing and loading the kubeconfig", func() {
+		conf := api.NewConfig()
+		kubeContext := api.NewContext()
+		kubeContext.Namespace = "team-a"
+		kubeContext.Extensions = SetManaged(kubeContext.Extensions, &Managed{Namespace: "jane-doe"})
+		conf.Contexts["managed"] = kubeContext
+		conf.Contexts["unmanaged"] = api.NewContext()
+
+		data, err := clientcmd.Write(*conf)
+		Expect(err).ToNot(HaveOccurred())
+		loaded, err := clientcmd.Load(data)
+		Expect(err).ToNot(HaveOccurred())
+
+		managed, ok := GetManaged(loaded.Contexts["managed"].Extensions)
+		Expect(ok).To(BeTrue())
+		Expect(managed.Namespace).To(Equal("jane-doe"))
+		Expect(IsManaged(loaded.Contexts["unmanaged"].Extensions)).To(BeFalse())
+	})
+})
@@ -0,0 +1,48 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type OutputFormat string
+
+const (
+	TableFormat OutputFormat = "table"
+	JSONFormat  OutputFormat = "json"
+)
+
+// ParseOutputFormat converts the given string into an OutputFormat. An empty string results in TableFormat.
+func ParseOutputFormat(format string) (OutputFormat, error) {
+	switch OutputFormat(strings.ToLower(format)) {
+	case "", TableFormat:
+		return TableFormat, nil
+	case JSONFormat:
+		return JSONFormat, nil
+	default:
+		return "", fmt.Errorf("output format '%s' is not supported, use one of: %s, %s", format, TableFormat, JSONFormat)
+	}
+}
+
+// WriteJSON writes the given value as indented JSON to the writer
+func WriteJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
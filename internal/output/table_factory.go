@@ -17,6 +17,7 @@ package output
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"sort"
@@ -37,6 +38,7 @@ type TableFactory struct {
 	sortColumn       string
 	exportFormat     ExportFormat
 	exportFile       string
+	output           io.Writer
 	header           []string
 	data             [][]interface{}
 	columnFormatters map[string]func(interface{}) string
@@ -49,6 +51,7 @@ func NewTableFactory() *TableFactory {
 	tf.sortOrder = Ascending
 	tf.exportFormat = CSV
 	tf.exportFile = ""
+	tf.output = os.Stdout
 	tf.columnFormatters = make(map[string]func(interface{}) string)
 	return tf
 }
@@ -95,6 +98,12 @@ func (tf *TableFactory) SetExportFile(exportFile string) *TableFactory {
 	return tf
 }
 
+// SetOutput sets the writer the table is rendered to unless exported to a file. Stdout is set by default
+func (tf *TableFactory) SetOutput(output io.Writer) *TableFactory {
+	tf.output = output
+	return tf
+}
+
 // SetHeader sets the header row of the table
 func (tf *TableFactory) SetHeader(header []string) *TableFactory {
 	tf.header = header
@@ -124,11 +133,11 @@ func (tf *TableFactory) ToTable() (*tablewriter.Table, error) {
 			return nil, errors.New("export format is not supported")
 		}
 	}
-	return tf.newStdoutTable()
+	return tf.newOutputTable()
 }
 
-func (tf *TableFactory) newStdoutTable() (*tablewriter.Table, error) {
-	tbl := tablewriter.NewWriter(os.Stdout)
+func (tf *TableFactory) newOutputTable() (*tablewriter.Table, error) {
+	tbl := tablewriter.NewWriter(tf.output)
 	tbl.SetAutoWrapText(false)
 	tbl.SetAutoFormatHeaders(true)
 	tbl.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
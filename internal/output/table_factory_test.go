@@ -15,7 +15,9 @@
 package output
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
 
@@ -100,6 +102,23 @@ var _ = Describe("Internal/Util/TableFactory", func() {
 		tbl.Render()
 	})
 
+	It("can render table to a writer", func() {
+		out := new(bytes.Buffer)
+		tbl, err := NewTableFactory().
+			SetOutput(out).
+			SetHeader([]string{"NAME", "VALUE"}).
+			SetData([][]interface{}{{"b", 2}, {"a", 1}}).
+			ToTable()
+		Expect(err).ToNot(HaveOccurred())
+
+		tbl.Render()
+		var rows [][]string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			rows = append(rows, strings.Fields(line))
+		}
+		Expect(rows).To(Equal([][]string{{"NAME", "VALUE"}, {"a", "1"}, {"b", "2"}}))
+	})
+
 	It("can export table to file", func() {
 		data = append(data, data[0]) // to ensure duplicate data/lines are not merged/skipped
 		tf.SetData(data)
@@ -1,9 +1,51 @@
 package prompt
 
 import (
+	"os"
+	"strings"
+
 	"github.com/manifoldco/promptui"
+	"golang.org/x/term"
 )
 
+// IsTerminal checks if stdin is a terminal, so the user can be prompted
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// Select prompts the user to select one of the specified items, typing filters the items fuzzily
+func Select(label string, items []string) (int, string, error) {
+	prompt := promptui.Select{
+		Label: label,
+		Items: items,
+		Size:  10,
+		Searcher: func(input string, index int) bool {
+			return FuzzyMatch(input, items[index])
+		},
+		StartInSearchMode: true,
+	}
+
+	idx, result, err := prompt.Run()
+	if err != nil {
+		return -1, "", err
+	}
+
+	return idx, result, nil
+}
+
+// FuzzyMatch checks if the characters of the input appear in the item in the same order, ignoring case and spaces
+func FuzzyMatch(input, item string) bool {
+	item = strings.ToLower(item)
+	for _, r := range strings.ToLower(strings.ReplaceAll(input, " ", "")) {
+		idx := strings.IndexRune(item, r)
+		if idx < 0 {
+			return false
+		}
+		item = item[idx+len(string(r)):]
+	}
+	return true
+}
+
 // SelectWithAdd prompts the user to select one of the specified options or write its own
 func SelectWithAdd(label string, AddLabel string, items []string) (int, string, error) {
 	prompt := promptui.SelectWithAdd{
@@ -39,6 +39,7 @@ type authUseCase struct {
 	configManager *config.ClientConfigManager
 	force         bool
 	silent        bool
+	renew         bool
 }
 
 func NewAuthUsecase(configManager *config.ClientConfigManager, force, silent bool) UseCase {
@@ -51,6 +52,16 @@ func NewAuthUsecase(configManager *config.ClientConfigManager, force, silent boo
 	return useCase
 }
 
+// NewRenewAuthUsecase returns a use-case running the interactive flow even if the current session is still valid.
+func NewRenewAuthUsecase(configManager *config.ClientConfigManager) UseCase {
+	useCase := &authUseCase{
+		useCaseBase:   NewUseCaseBase("authentication", configManager.GetConfig()),
+		configManager: configManager,
+		renew:         true,
+	}
+	return useCase
+}
+
 func (u *authUseCase) runAuthenticationFlow(ctx context.Context) error {
 	u.log.Info("starting authentication")
 	s := spinner.NewSpinner()
@@ -129,6 +140,17 @@ func (u *authUseCase) runAuthenticationFlow(ctx context.Context) error {
 		return err
 	}
 
+	u.setAuthInformation(authResponse)
+
+	s.Stop()
+	u.print("You're successfully authenticated as '%s'.\n", authResponse.GetUsername())
+	u.print("---\n")
+	u.print("\n")
+
+	return u.configManager.SaveConfig()
+}
+
+func (u *authUseCase) setAuthInformation(authResponse *api.AuthenticationResponse) {
 	u.config.AuthInformation = &config.AuthInformation{
 		Token:    authResponse.GetAccessToken(),
 		Username: authResponse.GetUsername(),
@@ -137,26 +159,21 @@ func (u *authUseCase) runAuthenticationFlow(ctx context.Context) error {
 		expiry := authResponse.GetExpiry().AsTime()
 		u.config.AuthInformation.Expiry = expiry
 	}
-
-	s.Stop()
-	u.print("You're successfully authenticated as '%s'.\n", authResponse.GetUsername())
-	u.print("---\n")
-	u.print("\n")
-
-	return u.configManager.SaveConfig()
 }
 
 func (u *authUseCase) Run(ctx context.Context) error {
 	// Check if already authenticated
-	if !u.force && u.config.HasAuthInformation() {
+	if !u.force && !u.renew && u.config.HasAuthInformation() {
 		u.log.Info("checking expiration of existing token")
-		authInfo := u.config.AuthInformation
-		if authInfo.IsValid() {
-			u.log.Info("you have a valid auth token", "expiry", authInfo.Expiry.String())
+		if u.config.IsAuthInformationValid() {
+			u.log.Info("you have a valid auth token", "expiry", u.config.AuthInformation.Expiry.String())
 			return nil
 		}
-		u.log.Info("your auth token has expired", "expiry", authInfo.Expiry)
+		u.log.Info("your auth token has expired", "expiry", u.config.AuthInformation.Expiry)
 	}
+
+	// The gateway issues its own tokens and keeps the upstream ones, it offers no way to renew
+	// a session without the user. Renewing is running the interactive flow ahead of time.
 	return u.runAuthenticationFlow(ctx)
 }
 
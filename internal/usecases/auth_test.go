@@ -15,7 +15,9 @@
 package usecases
 
 import (
+	"context"
 	_ "embed"
+	"time"
 
 	"github.com/finleap-connect/monoctl/internal/config"
 	"github.com/golang/mock/gomock"
@@ -66,3 +68,36 @@ var _ = Describe("render auth page", func() {
 		Expect(actualStatusPage).To(Equal(expectedStatusPage))
 	})
 })
+
+var _ = Describe("renew session", func() {
+	var (
+		ctx = context.Background()
+	)
+
+	newConfigManager := func() *config.ClientConfigManager {
+		conf := config.NewConfig()
+		conf.Server = "https://1.1.1.1"
+		conf.AuthInformation = &config.AuthInformation{
+			Username: "test-user",
+			Token:    "valid-token",
+			Expiry:   time.Now().UTC().Add(time.Hour),
+		}
+		return config.NewLoaderFromConfig(conf)
+	}
+
+	It("keeps a valid session", func() {
+		uc := NewAuthUsecase(newConfigManager(), false, true)
+		Expect(uc.Run(ctx)).To(Succeed())
+	})
+
+	It("runs the interactive flow even if the session is still valid", func() {
+		confManager := newConfigManager()
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel() // the interactive flow must not get anywhere in tests
+
+		uc := NewRenewAuthUsecase(confManager)
+		Expect(uc.Run(ctx)).ToNot(Succeed())
+		Expect(confManager.GetConfig().AuthInformation.Token).To(Equal("valid-token"))
+	})
+})
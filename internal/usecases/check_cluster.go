@@ -0,0 +1,376 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/k8s"
+	"github.com/finleap-connect/monoctl/internal/output"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	apiGateway "github.com/finleap-connect/monoskope/pkg/api/gateway"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	checkNameTLS            = "TLS"
+	checkNameCredentials    = "CREDENTIALS"
+	checkNameAuthentication = "AUTHENTICATION"
+	checkNameAuthorization  = "AUTHORIZATION"
+
+	// clusterCheckTimeout is the timeout of each request to the API server of the cluster
+	clusterCheckTimeout = 10 * time.Second
+)
+
+// systemCertPool returns the CAs trusted by the system
+var systemCertPool = x509.SystemCertPool
+
+// ClusterAccessCheck is the access verified by a SelfSubjectAccessReview when checking a cluster
+type ClusterAccessCheck struct {
+	Verb      string
+	Resource  string
+	Namespace string
+}
+
+func (c *ClusterAccessCheck) String() string {
+	if c.Namespace == "" {
+		return fmt.Sprintf("%s %s", c.Verb, c.Resource)
+	}
+	return fmt.Sprintf("%s %s in namespace %s", c.Verb, c.Resource, c.Namespace)
+}
+
+// clusterCheckResult is the result of a single check of a cluster
+type clusterCheckResult struct {
+	check   string
+	role    string
+	details string
+	err     error
+	skipped bool
+}
+
+// checkClusterUseCase provides the internal use-case of verifying that a cluster can be used with the credentials of m8
+type checkClusterUseCase struct {
+	useCaseBase
+	conn                *ggrpc.ClientConn
+	configManager       *config.ClientConfigManager
+	clusterClient       api.ClusterClient
+	clusterAccessClient api.ClusterAccessClient
+	clusterAuthClient   apiGateway.ClusterAuthClient
+	name                string
+	role                string
+	accessCheck         *ClusterAccessCheck
+	// results of the last run in the order the checks ran
+	results []*clusterCheckResult
+}
+
+// NewCheckClusterUseCase returns the use-case checking TLS, authentication and authorization against the cluster.
+// If no role is given, all roles the user has on the cluster are checked.
+func NewCheckClusterUseCase(configManager *config.ClientConfigManager, name, role string, accessCheck *ClusterAccessCheck) UseCase {
+	if accessCheck == nil {
+		accessCheck = &ClusterAccessCheck{}
+	}
+	if accessCheck.Verb == "" {
+		accessCheck.Verb = "get"
+	}
+	if accessCheck.Resource == "" {
+		accessCheck.Resource = "pods"
+	}
+	useCase := &checkClusterUseCase{
+		useCaseBase:   NewUseCaseBase("check-cluster", configManager.GetConfig()),
+		configManager: configManager,
+		name:          name,
+		role:          role,
+		accessCheck:   accessCheck,
+	}
+	return useCase
+}
+
+func (u *checkClusterUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
+
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if err != nil {
+		return err
+	}
+
+	u.conn = conn
+	u.clusterClient = api.NewClusterClient(u.conn)
+	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
+	u.clusterAuthClient = apiGateway.NewClusterAuthClient(u.conn)
+
+	u.setInitialized()
+
+	return nil
+}
+
+// getRoles returns the role to check or all roles the user has on the cluster
+func (u *checkClusterUseCase) getRoles(ctx context.Context, cluster *projections.Cluster) ([]string, error) {
+	if u.role != "" {
+		return []string{u.role}, nil
+	}
+
+	clusterAccesses, err := getClusterAccesses(ctx, u.clusterAccessClient)
+	if err != nil {
+		return nil, err
+	}
+	var roles []string
+	for _, clusterAccess := range clusterAccesses {
+		if clusterAccess.Cluster.Id != cluster.Id {
+			continue
+		}
+		for _, clusterRole := range clusterAccess.ClusterRoles {
+			roles = append(roles, clusterRole.Role)
+		}
+	}
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("you have no access to cluster '%s'", cluster.Name)
+	}
+	sort.Strings(roles)
+	return roles, nil
+}
+
+// addResult records the result of a check
+func (u *checkClusterUseCase) addResult(check, role, details string, err error) *clusterCheckResult {
+	result := &clusterCheckResult{check: check, role: role, details: details, err: err}
+	u.results = append(u.results, result)
+	return result
+}
+
+// skip records the checks which are skipped as a previous one failed
+func (u *checkClusterUseCase) skip(role, reason string, checks ...string) {
+	for _, check := range checks {
+		u.results = append(u.results, &clusterCheckResult{check: check, role: role, details: reason, skipped: true})
+	}
+}
+
+// checkTLS verifies the API server presents a certificate signed by the CA bundle of the cluster.
+// Without a CA bundle the system roots are trusted, as by the kubeconfig of the cluster.
+func (u *checkClusterUseCase) checkTLS(ctx context.Context, cluster *projections.Cluster) (string, error) {
+	var pool *x509.CertPool
+	if len(bytes.TrimSpace(cluster.CaCertBundle)) == 0 {
+		var err error
+		if pool, err = systemCertPool(); err != nil {
+			return "", err
+		}
+	} else {
+		certs, err := k8s.ParseCABundle(cluster.CaCertBundle)
+		if err != nil {
+			return "", err
+		}
+		pool = x509.NewCertPool()
+		for _, cert := range certs {
+			pool.AddCert(cert)
+		}
+	}
+
+	server, err := url.Parse(cluster.ApiServerAddress)
+	if err != nil {
+		return "", err
+	}
+	port := server.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, clusterCheckTimeout)
+	defer cancel()
+	dialer := &tls.Dialer{Config: &tls.Config{RootCAs: pool, ServerName: server.Hostname()}}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(server.Hostname(), port))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	serverCert := conn.(*tls.Conn).ConnectionState().PeerCertificates[0]
+	return fmt.Sprintf("certificate '%s' valid until %s", serverCert.Subject, serverCert.NotAfter.UTC().Format(time.RFC3339)), nil
+}
+
+// restConfig returns the config to talk to the API server of the cluster with the token
+func restConfig(cluster *projections.Cluster, token string) *rest.Config {
+	return &rest.Config{
+		Host:        cluster.ApiServerAddress,
+		BearerToken: token,
+		Timeout:     clusterCheckTimeout,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: cluster.CaCertBundle,
+		},
+	}
+}
+
+// checkAuthentication verifies the API server accepts the token by requesting its version
+func checkAuthentication(clientset kubernetes.Interface) (string, error) {
+	version, err := clientset.Discovery().ServerVersion()
+	if apierrors.IsUnauthorized(err) {
+		return "", errors.New("token rejected by the API server")
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Kubernetes %s", version.GitVersion), nil
+}
+
+// checkAuthorization verifies the access by a SelfSubjectAccessReview
+func (u *checkClusterUseCase) checkAuthorization(ctx context.Context, clientset kubernetes.Interface) (string, error) {
+	review, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:      u.accessCheck.Verb,
+				Resource:  u.accessCheck.Resource,
+				Namespace: u.accessCheck.Namespace,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	if !review.Status.Allowed {
+		if review.Status.Reason != "" {
+			return "", fmt.Errorf("not allowed to %s: %s", u.accessCheck, review.Status.Reason)
+		}
+		return "", fmt.Errorf("not allowed to %s", u.accessCheck)
+	}
+	return fmt.Sprintf("allowed to %s", u.accessCheck), nil
+}
+
+// checkRole checks authentication and authorization with the credentials of the role
+func (u *checkClusterUseCase) checkRole(ctx context.Context, cluster *projections.Cluster, role string) {
+	credentials := NewGetClusterCredentialsUseCase(u.configManager, cluster.Id, role).(*getClusterCredentialsUseCase)
+	credentials.clusterServiceClient = u.clusterClient
+	credentials.clusterAccessClient = u.clusterAccessClient
+	credentials.clusterAuthClient = u.clusterAuthClient
+	credentials.setInitialized()
+
+	authInfo, err := credentials.getClusterAuthInformation(ctx, cluster.Id)
+	if err != nil {
+		u.addResult(checkNameCredentials, role, "", err)
+		u.skip(role, "no credentials", checkNameAuthentication, checkNameAuthorization)
+		return
+	}
+	u.addResult(checkNameCredentials, role, fmt.Sprintf("token valid until %s", authInfo.Expiry.UTC().Format(time.RFC3339)), nil)
+
+	clientset, err := kubernetes.NewForConfig(restConfig(cluster, authInfo.Token))
+	if err != nil {
+		u.addResult(checkNameAuthentication, role, "", err)
+		u.skip(role, "authentication failed", checkNameAuthorization)
+		return
+	}
+
+	details, err := checkAuthentication(clientset)
+	u.addResult(checkNameAuthentication, role, details, err)
+	if err != nil {
+		u.skip(role, "authentication failed", checkNameAuthorization)
+		return
+	}
+
+	details, err = u.checkAuthorization(ctx, clientset)
+	u.addResult(checkNameAuthorization, role, details, err)
+}
+
+func (u *checkClusterUseCase) run(ctx context.Context) error {
+	cluster, err := u.clusterClient.GetByName(ctx, wrapperspb.String(u.name))
+	if err != nil {
+		return fmt.Errorf("failed getting cluster '%s': %w", u.name, err)
+	}
+
+	roles, err := u.getRoles(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	u.results = nil
+	details, err := u.checkTLS(ctx, cluster)
+	u.addResult(checkNameTLS, "", details, err)
+	for _, role := range roles {
+		if err != nil {
+			u.skip(role, "TLS failed", checkNameCredentials, checkNameAuthentication, checkNameAuthorization)
+			continue
+		}
+		u.checkRole(ctx, cluster, role)
+	}
+
+	var failed int
+	for _, result := range u.results {
+		if result.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks of cluster '%s' failed", failed, len(u.results), cluster.Name)
+	}
+	return nil
+}
+
+// printResults prints the result of every check
+func (u *checkClusterUseCase) printResults() error {
+	var data [][]interface{}
+	for _, result := range u.results {
+		text, details := "ok", result.details
+		switch {
+		case result.skipped:
+			text = "skipped"
+		case result.err != nil:
+			text, details = "failed", result.err.Error()
+		}
+		data = append(data, []interface{}{result.check, result.role, text, details})
+	}
+
+	tbl, err := output.NewTableFactory().
+		SetHeader([]string{"CHECK", "ROLE", "RESULT", "DETAILS"}).
+		SetData(data).
+		ToTable()
+	if err != nil {
+		return err
+	}
+	tbl.Render()
+	return nil
+}
+
+func (u *checkClusterUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	err = u.run(ctx)
+	if len(u.results) > 0 {
+		if printErr := u.printResults(); printErr != nil {
+			return printErr
+		}
+	}
+	return err
+}
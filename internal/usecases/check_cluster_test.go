@@ -0,0 +1,198 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mdomain "github.com/finleap-connect/monoctl/test/mock/domain"
+	mgw "github.com/finleap-connect/monoctl/test/mock/gateway"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	testutil_fs "github.com/kubism/testutil/pkg/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+var _ = Describe("CheckCluster", func() {
+	var (
+		mockCtrl *gomock.Controller
+		server   *httptest.Server
+		tempFile *testutil_fs.TempFile
+	)
+
+	var (
+		ctx          = context.Background()
+		expectedRole = "admin"
+		clusterToken = "some-cluster-token"
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+
+		// fake API server accepting the cluster token and allowing to get pods only
+		mux := http.NewServeMux()
+		authenticated := func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer "+clusterToken {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				next(w, r)
+			}
+		}
+		mux.HandleFunc("/version", authenticated(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"major":"1","minor":"26","gitVersion":"v1.26.2"}`))
+		}))
+		mux.HandleFunc("/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", authenticated(func(w http.ResponseWriter, r *http.Request) {
+			review := &authorizationv1.SelfSubjectAccessReview{}
+			if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			review.APIVersion, review.Kind = "authorization.k8s.io/v1", "SelfSubjectAccessReview"
+			review.Status.Allowed = review.Spec.ResourceAttributes.Verb == "get" && review.Spec.ResourceAttributes.Resource == "pods"
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(review)
+		}))
+		server = httptest.NewTLSServer(mux)
+	})
+
+	AfterEach(func() {
+		if tempFile != nil {
+			tempFile.Close()
+		}
+		server.Close()
+		mockCtrl.Finish()
+	})
+
+	// newCheckClusterUseCase returns the use-case for a cluster served by the fake API server having the token cached
+	newCheckClusterUseCase := func(token, role string, accessCheck *ClusterAccessCheck) (*checkClusterUseCase, *mdomain.MockClusterClient, *mdomain.MockClusterAccessClient, *projections.Cluster) {
+		var err error
+		tempFile, err = testutil_fs.NewTempFile([]byte(`server: https://1.1.1.1`))
+		Expect(err).NotTo(HaveOccurred())
+
+		confManager := config.NewLoaderFromExplicitFile(tempFile.Path)
+		Expect(confManager.LoadConfig()).NotTo(HaveOccurred())
+		confManager.GetConfig().AuthInformation = &config.AuthInformation{
+			Username: "test-user",
+			Expiry:   time.Now().Add(time.Hour),
+		}
+
+		cluster := &projections.Cluster{
+			Id:               uuid.New().String(),
+			Name:             "test-cluster",
+			ApiServerAddress: server.URL,
+			CaCertBundle:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+		}
+		confManager.GetConfig().SetClusterAuthInformation(cluster.Id, "test-user", expectedRole, token, time.Now().Add(time.Hour))
+
+		mockClusterClient := mdomain.NewMockClusterClient(mockCtrl)
+		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
+		mockClusterClient.EXPECT().GetByName(ctx, wrapperspb.String(cluster.Name)).Return(cluster, nil)
+
+		uc := NewCheckClusterUseCase(confManager, cluster.Name, role, accessCheck).(*checkClusterUseCase)
+		uc.clusterClient = mockClusterClient
+		uc.clusterAccessClient = mockClusterAccessClient
+		uc.clusterAuthClient = mgw.NewMockClusterAuthClient(mockCtrl)
+		uc.setInitialized()
+
+		return uc, mockClusterClient, mockClusterAccessClient, cluster
+	}
+
+	// resultsOf returns the check names with their result
+	resultsOf := func(uc *checkClusterUseCase) map[string]string {
+		results := make(map[string]string)
+		for _, result := range uc.results {
+			text := "ok"
+			if result.skipped {
+				text = "skipped"
+			} else if result.err != nil {
+				text = "failed"
+			}
+			results[result.check] = text
+		}
+		return results
+	}
+
+	It("checks all roles the user has on the cluster", func() {
+		uc, _, mockClusterAccessClient, cluster := newCheckClusterUseCase(clusterToken, "", nil)
+
+		getClusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+		getClusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+			Cluster:      cluster,
+			ClusterRoles: []*projections.ClusterRole{{Scope: projections.ClusterRole_CLUSTER, Role: expectedRole}},
+		}, nil)
+		getClusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(getClusterAccessClient, nil)
+
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(resultsOf(uc)).To(Equal(map[string]string{
+			checkNameTLS:            "ok",
+			checkNameCredentials:    "ok",
+			checkNameAuthentication: "ok",
+			checkNameAuthorization:  "ok",
+		}))
+	})
+	It("reports a rejected token and skips the authorization", func() {
+		uc, _, _, _ := newCheckClusterUseCase("invalid-token", expectedRole, nil)
+
+		err := uc.Run(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("1 of 4 checks"))
+		Expect(resultsOf(uc)).To(Equal(map[string]string{
+			checkNameTLS:            "ok",
+			checkNameCredentials:    "ok",
+			checkNameAuthentication: "failed",
+			checkNameAuthorization:  "skipped",
+		}))
+	})
+	It("reports denied access", func() {
+		uc, _, _, _ := newCheckClusterUseCase(clusterToken, expectedRole, &ClusterAccessCheck{Verb: "delete", Resource: "nodes"})
+
+		Expect(uc.Run(ctx)).To(HaveOccurred())
+		Expect(resultsOf(uc)[checkNameAuthorization]).To(Equal("failed"))
+		Expect(uc.results[len(uc.results)-1].err.Error()).To(Equal("not allowed to delete nodes"))
+	})
+	It("trusts the system roots if the cluster has no CA bundle", func() {
+		uc, _, _, cluster := newCheckClusterUseCase(clusterToken, expectedRole, nil)
+		cluster.CaCertBundle = nil
+
+		defer func(original func() (*x509.CertPool, error)) { systemCertPool = original }(systemCertPool)
+		systemCertPool = func() (*x509.CertPool, error) {
+			pool := x509.NewCertPool()
+			pool.AddCert(server.Certificate())
+			return pool, nil
+		}
+
+		// the fake API server is not trusted by the actual system roots the client of the cluster uses
+		Expect(uc.Run(ctx)).To(HaveOccurred())
+		Expect(resultsOf(uc)[checkNameTLS]).To(Equal("ok"))
+	})
+})
@@ -0,0 +1,159 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mdomain "github.com/finleap-connect/monoctl/test/mock/domain"
+	mgw "github.com/finleap-connect/monoctl/test/mock/gateway"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	gw "github.com/finleap-connect/monoskope/pkg/api/gateway"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	testutil_fs "github.com/kubism/testutil/pkg/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/zalando/go-keyring"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var _ = Describe("ClusterAuth", func() {
+	var (
+		ctx                     = context.Background()
+		mockCtrl                *gomock.Controller
+		mockClusterAccessClient *mdomain.MockClusterAccessClient
+		confManager             *config.ClientConfigManager
+		tempFile                *testutil_fs.TempFile
+		expectedExpiry          = time.Now().UTC().Add(1 * time.Hour)
+		firstCluster            = &projections.Cluster{Id: uuid.New().String(), Name: "first-cluster"}
+		secondCluster           = &projections.Cluster{Id: uuid.New().String(), Name: "second-cluster"}
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		keyring.MockInit()
+
+		var err error
+		tempFile, err = testutil_fs.NewTempFile([]byte(`server: https://1.1.1.1`))
+		Expect(err).NotTo(HaveOccurred())
+
+		confManager = config.NewLoaderFromExplicitFile(tempFile.Path)
+		Expect(confManager.LoadConfig()).NotTo(HaveOccurred())
+		confManager.GetConfig().AuthInformation = &config.AuthInformation{
+			Username: "test-user",
+			Expiry:   expectedExpiry,
+		}
+
+		mockClusterAccessClient = mdomain.NewMockClusterAccessClient(mockCtrl)
+		getClusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+		getClusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+			Cluster:      firstCluster,
+			ClusterRoles: []*projections.ClusterRole{{Scope: projections.ClusterRole_CLUSTER, Role: "admin"}, {Scope: projections.ClusterRole_CLUSTER, Role: "default"}},
+		}, nil)
+		getClusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+			Cluster:      secondCluster,
+			ClusterRoles: []*projections.ClusterRole{{Scope: projections.ClusterRole_CLUSTER, Role: "default"}},
+		}, nil)
+		getClusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(getClusterAccessClient, nil)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+		tempFile.Close()
+	})
+
+	Context("refresh", func() {
+		var mockClusterAuthClient *mgw.MockClusterAuthClient
+
+		BeforeEach(func() {
+			mockClusterAuthClient = mgw.NewMockClusterAuthClient(mockCtrl)
+		})
+
+		newUseCase := func() *refreshClusterAuthUseCase {
+			uc := NewRefreshClusterAuthUseCase(confManager, 2).(*refreshClusterAuthUseCase)
+			uc.clusterAccessClient = mockClusterAccessClient
+			uc.clusterAuthClient = mockClusterAuthClient
+			uc.setInitialized()
+			return uc
+		}
+
+		It("fetches the tokens of all accessible clusters and roles", func() {
+			mockClusterAuthClient.EXPECT().GetAuthToken(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, request *gw.ClusterAuthTokenRequest, _ ...interface{}) (*gw.ClusterAuthTokenResponse, error) {
+					return &gw.ClusterAuthTokenResponse{
+						AccessToken: request.ClusterId + request.Role,
+						Expiry:      timestamppb.New(expectedExpiry),
+					}, nil
+				}).Times(3)
+
+			Expect(newUseCase().Run(ctx)).To(Succeed())
+
+			c := confManager.GetConfig()
+			Expect(c.GetClusterAuthInformation(firstCluster.Id, "test-user", "admin").Token).To(Equal(firstCluster.Id + "admin"))
+			Expect(c.GetClusterAuthInformation(firstCluster.Id, "test-user", "default").Token).To(Equal(firstCluster.Id + "default"))
+			Expect(c.GetClusterAuthInformation(secondCluster.Id, "test-user", "default").Token).To(Equal(secondCluster.Id + "default"))
+		})
+
+		It("reports clusters failing without aborting the others", func() {
+			mockClusterAuthClient.EXPECT().GetAuthToken(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, request *gw.ClusterAuthTokenRequest, _ ...interface{}) (*gw.ClusterAuthTokenResponse, error) {
+					if request.ClusterId == secondCluster.Id {
+						return nil, errors.New("cluster unreachable")
+					}
+					return &gw.ClusterAuthTokenResponse{AccessToken: "token", Expiry: timestamppb.New(expectedExpiry)}, nil
+				}).Times(3)
+
+			uc := newUseCase()
+			Expect(uc.Run(ctx)).To(MatchError("failed fetching 1 of 3 cluster tokens"))
+			Expect(uc.results).To(HaveLen(3))
+
+			c := confManager.GetConfig()
+			Expect(c.GetClusterAuthInformation(firstCluster.Id, "test-user", "admin")).ToNot(BeNil())
+			Expect(c.GetClusterAuthInformation(secondCluster.Id, "test-user", "default")).To(BeNil())
+		})
+	})
+
+	Context("prune", func() {
+		It("removes tokens which are not usable anymore", func() {
+			c := confManager.GetConfig()
+			c.SetClusterAuthInformation(firstCluster.Id, "test-user", "admin", "valid", expectedExpiry)
+			c.SetClusterAuthInformation(firstCluster.Id, "test-user", "default", "expired", time.Now().Add(-time.Hour))
+			c.SetClusterAuthInformation(secondCluster.Id, "other-user", "default", "other", expectedExpiry)
+			c.SetClusterAuthInformation(uuid.New().String(), "test-user", "default", "no-access", expectedExpiry)
+
+			out := new(bytes.Buffer)
+			uc := NewPruneClusterAuthUseCase(confManager).(*pruneClusterAuthUseCase)
+			uc.clusterAccessClient = mockClusterAccessClient
+			uc.out = out
+			uc.setInitialized()
+			Expect(uc.Run(ctx)).To(Succeed())
+
+			Expect(c.ClusterAuthInformation).To(HaveLen(2))
+			Expect(c.GetClusterAuthInformation(firstCluster.Id, "test-user", "admin")).ToNot(BeNil())
+			Expect(c.GetClusterAuthInformation(secondCluster.Id, "other-user", "default")).ToNot(BeNil())
+			Expect(out.String()).To(ContainSubstring("Removed token for role 'default' on cluster 'first-cluster': expired."))
+			Expect(out.String()).ToNot(ContainSubstring("second-cluster"))
+			Expect(out.String()).To(ContainSubstring(": no access anymore."))
+		})
+	})
+})
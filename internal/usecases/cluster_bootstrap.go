@@ -0,0 +1,216 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/jwt"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Monoskope onboards a cluster by its KubeAPIServer trusting the gateway as OIDC issuer of cluster tokens, there is
+// nothing deployed to the cluster. Roles are bound to the users by the RBAC reconciler of Monoskope.
+const (
+	// DefaultBootstrapUsernamePrefix is the default username prefix of the RBAC reconciler of Monoskope
+	DefaultBootstrapUsernamePrefix = "oidc:"
+	// bootstrapClientID is the audience of cluster tokens
+	bootstrapClientID = "k8sauth"
+	// bootstrapUsernameClaim is the claim of cluster tokens containing the name of the user in the cluster
+	bootstrapUsernameClaim = "cluster_username"
+	// bootstrapGroupsClaim is the claim of cluster tokens containing the role of the user in the cluster
+	bootstrapGroupsClaim = "cluster_role"
+	// bootstrapRequiredClaim is the claim of cluster tokens containing the name of the cluster they are valid for
+	bootstrapRequiredClaim = "cluster_name"
+)
+
+//go:embed bootstrap/*.yaml
+var bootstrapTemplates embed.FS
+
+// BootstrapOptions defines how the onboarding manifests of a cluster are rendered
+type BootstrapOptions struct {
+	// Dir to write the manifests to, if empty they are written to stdout as one stream
+	Dir string
+	// IssuerURL of the cluster tokens, defaults to the issuer of the current session
+	IssuerURL string
+	// UsernamePrefix has to match the one of the RBAC reconciler of Monoskope, defaults to DefaultBootstrapUsernamePrefix
+	UsernamePrefix string
+}
+
+func (o *BootstrapOptions) setDefaults(conf *config.Config) {
+	if o.IssuerURL == "" {
+		o.IssuerURL = bootstrapIssuerURL(conf)
+	}
+	if o.UsernamePrefix == "" {
+		o.UsernamePrefix = DefaultBootstrapUsernamePrefix
+	}
+}
+
+// bootstrapIssuerURL returns the issuer of the tokens of the gateway. It is taken from the token of the current
+// session, falling back to the host of the configured server.
+func bootstrapIssuerURL(conf *config.Config) string {
+	if conf.HasAuthInformation() {
+		if claims, err := jwt.ParseUnverified(conf.AuthInformation.Token); err == nil && claims.Claims != nil && claims.Issuer != "" {
+			return claims.Issuer
+		}
+	}
+
+	server := conf.Server
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+	u, err := url.Parse(server)
+	if err != nil || u.Hostname() == "" {
+		return server
+	}
+	if u.Port() == "" || u.Port() == "443" {
+		return "https://" + u.Hostname()
+	}
+	return "https://" + u.Host
+}
+
+// renderBootstrapManifests renders the onboarding manifests of the cluster to the directory of the options or to out
+func renderBootstrapManifests(data *ClusterRenderData, options *BootstrapOptions, out io.Writer) error {
+	names, err := fs.Glob(bootstrapTemplates, "bootstrap/*.yaml")
+	if err != nil {
+		return err
+	}
+	funcs := template.FuncMap{"quote": strconv.Quote}
+
+	if options.Dir != "" {
+		if err := os.MkdirAll(options.Dir, 0755); err != nil {
+			return err
+		}
+	}
+	for i, name := range names {
+		tmpl, err := template.New(path.Base(name)).Funcs(funcs).Option("missingkey=error").ParseFS(bootstrapTemplates, name)
+		if err != nil {
+			return err
+		}
+		buf := new(bytes.Buffer)
+		if err := tmpl.Execute(buf, data); err != nil {
+			return fmt.Errorf("failed rendering %s: %w", path.Base(name), err)
+		}
+
+		if options.Dir == "" {
+			if i > 0 {
+				fmt.Fprintln(out, "---")
+			}
+			if _, err := out.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+		file := filepath.Join(options.Dir, path.Base(name))
+		if err := os.WriteFile(file, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Wrote %s\n", file)
+	}
+	return nil
+}
+
+// bootstrapCluster renders the onboarding manifests of the cluster
+func bootstrapCluster(conf *config.Config, clusterName, apiServerAddress string, options *BootstrapOptions, out io.Writer) error {
+	options.setDefaults(conf)
+	return renderBootstrapManifests(&ClusterRenderData{
+		ApiServerAddress: apiServerAddress,
+		ClusterName:      clusterName,
+		IssuerURL:        options.IssuerURL,
+		ClientID:         bootstrapClientID,
+		UsernameClaim:    bootstrapUsernameClaim,
+		UsernamePrefix:   options.UsernamePrefix,
+		GroupsClaim:      bootstrapGroupsClaim,
+		RequiredClaim:    bootstrapRequiredClaim,
+	}, options, out)
+}
+
+// getClusterBootstrapUseCase provides the internal use-case of regenerating the onboarding manifests of a cluster
+type getClusterBootstrapUseCase struct {
+	useCaseBase
+	conn          *ggrpc.ClientConn
+	clusterClient api.ClusterClient
+	name          string
+	options       *BootstrapOptions
+	out           io.Writer
+}
+
+// NewGetClusterBootstrapUseCase returns the use-case rendering the onboarding manifests of the cluster
+func NewGetClusterBootstrapUseCase(config *config.Config, name string, options *BootstrapOptions) UseCase {
+	if options == nil {
+		options = &BootstrapOptions{}
+	}
+	useCase := &getClusterBootstrapUseCase{
+		useCaseBase: NewUseCaseBase("get-cluster-bootstrap", config),
+		name:        name,
+		options:     options,
+		out:         os.Stdout,
+	}
+	return useCase
+}
+
+func (u *getClusterBootstrapUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
+
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if err != nil {
+		return err
+	}
+
+	u.conn = conn
+	u.clusterClient = api.NewClusterClient(u.conn)
+
+	u.setInitialized()
+
+	return nil
+}
+
+func (u *getClusterBootstrapUseCase) run(ctx context.Context) error {
+	cluster, err := u.clusterClient.GetByName(ctx, wrapperspb.String(u.name))
+	if err != nil {
+		return err
+	}
+	return bootstrapCluster(u.config, cluster.Name, cluster.ApiServerAddress, u.options, u.out)
+}
+
+func (u *getClusterBootstrapUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	return u.run(ctx)
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mdom "github.com/finleap-connect/monoctl/test/mock/domain"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	mjwt "github.com/finleap-connect/monoskope/pkg/jwt"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gopkg.in/square/go-jose.v2/jwt"
+	"gopkg.in/yaml.v2"
+)
+
+var _ = Describe("ClusterBootstrap", func() {
+	var (
+		ctx      = context.Background()
+		mockCtrl *gomock.Controller
+		cluster  = &projections.Cluster{
+			Id:               uuid.New().String(),
+			Name:             "one-cluster",
+			ApiServerAddress: "https://one.example.com",
+		}
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("renders the KubeAPIServer configuration of an existing cluster to stdout", func() {
+		conf := config.NewConfig()
+		conf.Server = "m8.example.com:443"
+
+		mockClusterClient := mdom.NewMockClusterClient(mockCtrl)
+		mockClusterClient.EXPECT().GetByName(ctx, wrapperspb.String(cluster.Name)).Return(cluster, nil)
+
+		out := new(bytes.Buffer)
+		uc := NewGetClusterBootstrapUseCase(conf, cluster.Name, nil).(*getClusterBootstrapUseCase)
+		uc.clusterClient = mockClusterClient
+		uc.out = out
+		uc.setInitialized()
+		Expect(uc.Run(ctx)).To(Succeed())
+
+		var manifests []map[string]interface{}
+		decoder := yaml.NewDecoder(out)
+		for {
+			manifest := make(map[string]interface{})
+			if err := decoder.Decode(&manifest); err != nil {
+				break
+			}
+			manifests = append(manifests, manifest)
+		}
+		Expect(manifests).To(HaveLen(2))
+
+		Expect(manifests[0]["kind"]).To(Equal("ClusterConfiguration"))
+		apiServer := manifests[0]["apiServer"].(map[interface{}]interface{})
+		Expect(apiServer["extraArgs"]).To(Equal(map[interface{}]interface{}{
+			"oidc-issuer-url":      "https://m8.example.com",
+			"oidc-client-id":       "k8sauth",
+			"oidc-username-claim":  "cluster_username",
+			"oidc-username-prefix": DefaultBootstrapUsernamePrefix,
+			"oidc-groups-claim":    "cluster_role",
+			"oidc-required-claim":  "cluster_name=" + cluster.Name,
+		}))
+
+		shoot := manifests[1]["spec"].(map[interface{}]interface{})["kubernetes"].(map[interface{}]interface{})["kubeAPIServer"].(map[interface{}]interface{})
+		Expect(shoot["oidcConfig"]).To(Equal(map[interface{}]interface{}{
+			"issuerURL":      "https://m8.example.com",
+			"clientID":       "k8sauth",
+			"usernameClaim":  "cluster_username",
+			"usernamePrefix": DefaultBootstrapUsernamePrefix,
+			"groupsClaim":    "cluster_role",
+			"requiredClaims": map[interface{}]interface{}{"cluster_name": cluster.Name},
+		}))
+	})
+
+	It("writes the manifests to the directory", func() {
+		dir, err := os.MkdirTemp("", "monoctl-bootstrap")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		outDir := filepath.Join(dir, "one-cluster")
+
+		out := new(bytes.Buffer)
+		options := &BootstrapOptions{Dir: outDir, IssuerURL: "https://issuer.example.com", UsernamePrefix: "m8:"}
+		Expect(bootstrapCluster(config.NewConfig(), cluster.Name, cluster.ApiServerAddress, options, out)).To(Succeed())
+
+		entries, err := os.ReadDir(outDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(2))
+		Expect(strings.Count(out.String(), "Wrote ")).To(Equal(2))
+
+		kubeAPIServer, err := os.ReadFile(filepath.Join(outDir, "00-kube-apiserver.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(kubeAPIServer)).To(ContainSubstring(`oidc-issuer-url: "https://issuer.example.com"`))
+		Expect(string(kubeAPIServer)).To(ContainSubstring(`oidc-username-prefix: "m8:"`))
+	})
+
+	It("takes the issuer from the token of the current session", func() {
+		conf := config.NewConfig()
+		conf.Server = "m8.example.com:8443"
+		Expect(bootstrapIssuerURL(conf)).To(Equal("https://m8.example.com:8443"))
+
+		conf.AuthInformation = &config.AuthInformation{Token: newTestToken(&mjwt.AuthToken{Claims: &jwt.Claims{Issuer: "https://issuer.example.com"}})}
+		Expect(bootstrapIssuerURL(conf)).To(Equal("https://issuer.example.com"))
+	})
+})
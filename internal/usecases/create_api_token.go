@@ -15,35 +15,76 @@
 package usecases
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/finleap-connect/monoctl/internal/config"
 	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/jwt"
+	"github.com/finleap-connect/monoctl/internal/k8s"
+	"github.com/finleap-connect/monoctl/internal/output"
 	apiGateway "github.com/finleap-connect/monoskope/pkg/api/gateway"
 	"github.com/google/uuid"
 	ggrpc "google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/durationpb"
+	kapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
+// apiTokenEnvVar is the environment variable the token is exported as
+const apiTokenEnvVar = "MONOCTL_TOKEN"
+
+// APITokenOutputOptions defines how and where an issued API token is delivered
+type APITokenOutputOptions struct {
+	// Format of the output, by default only the raw token is printed
+	Format output.OutputFormat
+	// File to write the output to instead of stdout, created with 0600 permissions
+	File string
+	// KubeconfigUser is the name of the kubeconfig user entry the token is stored in
+	KubeconfigUser string
+	// Env prints an export statement of the token instead of the raw token
+	Env bool
+}
+
+type apiTokenOutput struct {
+	Token  string    `json:"token"`
+	Expiry time.Time `json:"expiry"`
+	Scopes []string  `json:"scopes"`
+	UserId string    `json:"userId"`
+}
+
 type createAPITokenUsecase struct {
 	useCaseBase
 	conn           *ggrpc.ClientConn
 	configManager  *config.ClientConfigManager
 	apiTokenClient apiGateway.APITokenClient
+	kubeConfig     *k8s.KubeConfig
 	userId         string
 	scopes         []string
 	validity       time.Duration
+	outputOptions  *APITokenOutputOptions
+	out            io.Writer
 }
 
-func NewCreateAPITokenUsecase(configManager *config.ClientConfigManager, userId string, scopes []string, validity time.Duration) UseCase {
+func NewCreateAPITokenUsecase(configManager *config.ClientConfigManager, userId string, scopes []string, validity time.Duration, outputOptions *APITokenOutputOptions) UseCase {
 	useCase := &createAPITokenUsecase{
 		useCaseBase:   NewUseCaseBase("create-api-token", configManager.GetConfig()),
 		configManager: configManager,
+		kubeConfig:    k8s.NewKubeConfig(),
 		userId:        userId,
 		scopes:        scopes,
 		validity:      validity,
+		outputOptions: outputOptions,
+		out:           os.Stdout,
+	}
+	if useCase.outputOptions == nil {
+		useCase.outputOptions = &APITokenOutputOptions{}
 	}
 	return useCase
 }
@@ -66,10 +107,48 @@ func (u *createAPITokenUsecase) init(ctx context.Context) error {
 	return nil
 }
 
-func (u *createAPITokenUsecase) run(ctx context.Context) error {
+// AvailableAPITokenScopes returns the names of all scopes an API token can be issued for, sorted by their value
+func AvailableAPITokenScopes() []string {
+	var availableScopes []string
+	for value, name := range apiGateway.AuthorizationScope_name {
+		if value == int32(apiGateway.AuthorizationScope_NONE) {
+			continue
+		}
+		availableScopes = append(availableScopes, name)
+	}
+	sort.Slice(availableScopes, func(i, j int) bool {
+		return apiGateway.AuthorizationScope_value[availableScopes[i]] < apiGateway.AuthorizationScope_value[availableScopes[j]]
+	})
+	return availableScopes
+}
+
+// parseScopes converts the given scope names into AuthorizationScopes and rejects unknown ones
+func parseScopes(scopes []string) ([]apiGateway.AuthorizationScope, error) {
 	var authScopes []apiGateway.AuthorizationScope
-	for _, scope := range u.scopes {
-		authScopes = append(authScopes, apiGateway.AuthorizationScope(apiGateway.AuthorizationScope_value[scope]))
+	for _, scope := range scopes {
+		value, ok := apiGateway.AuthorizationScope_value[scope]
+		if !ok || value == int32(apiGateway.AuthorizationScope_NONE) {
+			return nil, fmt.Errorf("scope '%s' is unknown, available scopes: %s", scope, strings.Join(AvailableAPITokenScopes(), ", "))
+		}
+		authScopes = append(authScopes, apiGateway.AuthorizationScope(value))
+	}
+	return authScopes, nil
+}
+
+func (u *createAPITokenUsecase) validateOutputOptions() error {
+	if u.outputOptions.Env && u.outputOptions.Format == output.JSONFormat {
+		return errors.New("--env can not be combined with json output")
+	}
+	return nil
+}
+
+func (u *createAPITokenUsecase) run(ctx context.Context) error {
+	authScopes, err := parseScopes(u.scopes)
+	if err != nil {
+		return err
+	}
+	if err := u.validateOutputOptions(); err != nil {
+		return err
 	}
 
 	request := &apiGateway.APITokenRequest{
@@ -93,11 +172,132 @@ func (u *createAPITokenUsecase) run(ctx context.Context) error {
 		return err
 	}
 
-	fmt.Println(response.GetAccessToken())
+	if err := u.deliver(response); err != nil {
+		return err
+	}
+
+	// The token has been issued already, failing to record it must not fail the command
+	if err := u.recordAPIToken(response); err != nil {
+		u.log.Info("failed to record API token", "error", err.Error())
+	}
+
+	return nil
+}
+
+// deliver hands the issued token to the configured output targets
+func (u *createAPITokenUsecase) deliver(response *apiGateway.APITokenResponse) error {
+	if u.outputOptions.KubeconfigUser != "" {
+		if err := u.storeInKubeconfig(response.GetAccessToken()); err != nil {
+			return err
+		}
+	}
+
+	content, err := u.render(response)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case u.outputOptions.File != "":
+		if err := writePrivateFile(u.outputOptions.File, content); err != nil {
+			return err
+		}
+		fmt.Fprintf(u.out, "API token written to '%s'.\n", u.outputOptions.File)
+	case u.outputOptions.KubeconfigUser != "" && !u.outputOptions.Env && u.outputOptions.Format != output.JSONFormat:
+		fmt.Fprintf(u.out, "API token stored as user '%s' in kubeconfig '%s'.\n", u.outputOptions.KubeconfigUser, u.kubeConfig.ConfigPath)
+	default:
+		_, err := u.out.Write(content)
+		return err
+	}
 
 	return nil
 }
 
+// render formats the issued token according to the output options
+func (u *createAPITokenUsecase) render(response *apiGateway.APITokenResponse) ([]byte, error) {
+	token := response.GetAccessToken()
+	switch {
+	case u.outputOptions.Env:
+		return []byte(fmt.Sprintf("export %s=%s\n", apiTokenEnvVar, token)), nil
+	case u.outputOptions.Format == output.JSONFormat:
+		userId := u.userId
+		if claims, err := jwt.ParseUnverified(token); err == nil && claims.Claims != nil && claims.Subject != "" {
+			userId = claims.Subject
+		}
+		buf := new(bytes.Buffer)
+		err := output.WriteJSON(buf, &apiTokenOutput{
+			Token:  token,
+			Expiry: response.GetExpiry().AsTime(),
+			Scopes: u.scopes,
+			UserId: userId,
+		})
+		return buf.Bytes(), err
+	default:
+		return []byte(token + "\n"), nil
+	}
+}
+
+// storeInKubeconfig writes the token as user entry to the kubeconfig
+func (u *createAPITokenUsecase) storeInKubeconfig(token string) error {
+	if len(u.kubeConfig.ConfigPath) == 0 {
+		u.kubeConfig.SetPath(u.config.KubeConfigPath)
+	}
+	kubeConfig, err := u.kubeConfig.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	authInfo := kapi.NewAuthInfo()
+	authInfo.Token = token
+	kubeConfig.AuthInfos[u.outputOptions.KubeconfigUser] = authInfo
+
+	return u.kubeConfig.StoreConfig(kubeConfig)
+}
+
+// writePrivateFile writes the content to the file, making sure only the owner can read it
+func writePrivateFile(name string, content []byte) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	// Tighten permissions of already existing files
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// recordAPIToken keeps a local record of the issued token in the monoconfig, the m8 control plane does not keep track of issued tokens
+func (u *createAPITokenUsecase) recordAPIToken(response *apiGateway.APITokenResponse) error {
+	claims, err := jwt.ParseUnverified(response.GetAccessToken())
+	if err != nil {
+		return err
+	}
+	if claims.Claims == nil || claims.ID == "" {
+		return errors.New("token has no id")
+	}
+
+	created := time.Now().UTC()
+	if claims.IssuedAt != nil {
+		created = claims.IssuedAt.Time().UTC()
+	}
+	u.config.AddAPIToken(&config.APITokenInformation{
+		Id:       claims.ID,
+		User:     u.userId,
+		Scopes:   u.scopes,
+		Validity: u.validity,
+		Created:  created,
+		Expiry:   response.GetExpiry().AsTime(),
+	})
+
+	return u.configManager.SaveConfig()
+}
+
 func (u *createAPITokenUsecase) Run(ctx context.Context) error {
 	err := u.init(ctx)
 	if err != nil {
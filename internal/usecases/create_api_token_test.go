@@ -15,13 +15,19 @@
 package usecases
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/output"
 	mgw "github.com/finleap-connect/monoctl/test/mock/gateway"
 	gw "github.com/finleap-connect/monoskope/pkg/api/gateway"
+	mjwt "github.com/finleap-connect/monoskope/pkg/jwt"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	testutil_fs "github.com/kubism/testutil/pkg/fs"
@@ -29,6 +35,8 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/zalando/go-keyring"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/square/go-jose.v2/jwt"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 var _ = Describe("internal/usecases/create_api_token", func() {
@@ -72,7 +80,7 @@ var _ = Describe("internal/usecases/create_api_token", func() {
 		}
 
 		apiTokenClient := mgw.NewMockAPITokenClient(mockCtrl)
-		uc := NewCreateAPITokenUsecase(confManager, expectedUserId.String(), expectedScopes, expectedValidity).(*createAPITokenUsecase)
+		uc := NewCreateAPITokenUsecase(confManager, expectedUserId.String(), expectedScopes, expectedValidity, nil).(*createAPITokenUsecase)
 		uc.apiTokenClient = apiTokenClient
 		uc.setInitialized()
 
@@ -85,4 +93,160 @@ var _ = Describe("internal/usecases/create_api_token", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("should record the issued token", func() {
+		keyring.MockInit()
+
+		tempFile, err := testutil_fs.NewTempFile([]byte(fakeConfigData))
+		Expect(err).NotTo(HaveOccurred())
+		defer tempFile.Close()
+
+		confManager := config.NewLoaderFromExplicitFile(tempFile.Path)
+		Expect(confManager.LoadConfig()).NotTo(HaveOccurred())
+
+		confManager.GetConfig().AuthInformation = &config.AuthInformation{
+			Username: "test-user",
+			Expiry:   expectedExpiry,
+		}
+
+		tokenId := uuid.New().String()
+		issuedToken := newTestToken(&mjwt.AuthToken{
+			Claims: &jwt.Claims{
+				ID:       tokenId,
+				Subject:  expectedUserId.String(),
+				IssuedAt: jwt.NewNumericDate(time.Now()),
+				Expiry:   jwt.NewNumericDate(expectedExpiry),
+			},
+			IsAPIToken: true,
+		})
+
+		apiTokenClient := mgw.NewMockAPITokenClient(mockCtrl)
+		uc := NewCreateAPITokenUsecase(confManager, expectedUserId.String(), expectedScopes, expectedValidity, nil).(*createAPITokenUsecase)
+		uc.apiTokenClient = apiTokenClient
+		uc.setInitialized()
+
+		apiTokenClient.EXPECT().RequestAPIToken(ctx, gomock.Any()).Return(&gw.APITokenResponse{
+			AccessToken: issuedToken,
+			Expiry:      timestamppb.New(expectedExpiry),
+		}, nil)
+
+		Expect(uc.Run(ctx)).To(Succeed())
+
+		Expect(confManager.LoadConfig()).To(Succeed())
+		apiToken := confManager.GetConfig().GetAPIToken(tokenId)
+		Expect(apiToken).ToNot(BeNil())
+		Expect(apiToken.User).To(Equal(expectedUserId.String()))
+		Expect(apiToken.Scopes).To(Equal(expectedScopes))
+		Expect(apiToken.Validity).To(Equal(expectedValidity))
+		Expect(apiToken.Status()).To(Equal("valid"))
+	})
+
+	Context("output targets", func() {
+		var (
+			confManager    *config.ClientConfigManager
+			apiTokenClient *mgw.MockAPITokenClient
+			tempDir        string
+		)
+
+		BeforeEach(func() {
+			keyring.MockInit()
+
+			var err error
+			tempDir, err = os.MkdirTemp("", "monoctl-api-token")
+			Expect(err).ToNot(HaveOccurred())
+			configFile := filepath.Join(tempDir, "monoconfig")
+			Expect(os.WriteFile(configFile, []byte(fakeConfigData), 0600)).To(Succeed())
+
+			confManager = config.NewLoaderFromExplicitFile(configFile)
+			Expect(confManager.LoadConfig()).NotTo(HaveOccurred())
+			confManager.GetConfig().AuthInformation = &config.AuthInformation{
+				Username: "test-user",
+				Expiry:   expectedExpiry,
+			}
+
+			apiTokenClient = mgw.NewMockAPITokenClient(mockCtrl)
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+
+		newUseCase := func(outputOptions *APITokenOutputOptions) (*createAPITokenUsecase, *bytes.Buffer) {
+			uc := NewCreateAPITokenUsecase(confManager, expectedUserId.String(), expectedScopes, expectedValidity, outputOptions).(*createAPITokenUsecase)
+			uc.apiTokenClient = apiTokenClient
+			uc.setInitialized()
+			buf := new(bytes.Buffer)
+			uc.out = buf
+			return uc, buf
+		}
+
+		expectRequest := func() {
+			apiTokenClient.EXPECT().RequestAPIToken(ctx, gomock.Any()).Return(&gw.APITokenResponse{
+				AccessToken: expectedToken,
+				Expiry:      timestamppb.New(expectedExpiry),
+			}, nil)
+		}
+
+		It("should print json", func() {
+			expectRequest()
+			uc, buf := newUseCase(&APITokenOutputOptions{Format: output.JSONFormat})
+			Expect(uc.Run(ctx)).To(Succeed())
+
+			result := make(map[string]interface{})
+			Expect(json.Unmarshal(buf.Bytes(), &result)).To(Succeed())
+			Expect(result["token"]).To(Equal(expectedToken))
+			Expect(result["userId"]).To(Equal(expectedUserId.String()))
+			Expect(result["scopes"]).To(ConsistOf(gw.AuthorizationScope_API.String()))
+			Expect(result["expiry"]).To(Equal(expectedExpiry.Format(time.RFC3339Nano)))
+		})
+
+		It("should print an export statement", func() {
+			expectRequest()
+			uc, buf := newUseCase(&APITokenOutputOptions{Env: true})
+			Expect(uc.Run(ctx)).To(Succeed())
+			Expect(buf.String()).To(Equal("export MONOCTL_TOKEN=" + expectedToken + "\n"))
+		})
+
+		It("should write the token to a private file", func() {
+			expectRequest()
+			outputFile := filepath.Join(tempDir, "token")
+			Expect(os.WriteFile(outputFile, []byte("old"), 0644)).To(Succeed())
+
+			uc, buf := newUseCase(&APITokenOutputOptions{File: outputFile})
+			Expect(uc.Run(ctx)).To(Succeed())
+			Expect(buf.String()).ToNot(ContainSubstring(expectedToken))
+
+			content, err := os.ReadFile(outputFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal(expectedToken + "\n"))
+
+			info, err := os.Stat(outputFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+
+		It("should store the token in the kubeconfig", func() {
+			expectRequest()
+			kubeConfigPath := filepath.Join(tempDir, "kubeconfig")
+			confManager.GetConfig().KubeConfigPath = kubeConfigPath
+
+			uc, buf := newUseCase(&APITokenOutputOptions{KubeconfigUser: "ci"})
+			Expect(uc.Run(ctx)).To(Succeed())
+			Expect(buf.String()).ToNot(ContainSubstring(expectedToken))
+
+			kubeConfig, err := clientcmd.LoadFromFile(kubeConfigPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kubeConfig.AuthInfos).To(HaveKey("ci"))
+			Expect(kubeConfig.AuthInfos["ci"].Token).To(Equal(expectedToken))
+		})
+
+		It("should reject unknown scopes before sending the request", func() {
+			uc := NewCreateAPITokenUsecase(confManager, expectedUserId.String(), []string{"API", "EVERYTHING"}, expectedValidity, nil).(*createAPITokenUsecase)
+			uc.apiTokenClient = apiTokenClient
+			uc.setInitialized()
+
+			err := uc.Run(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("scope 'EVERYTHING' is unknown"))
+		})
+	})
 })
@@ -17,11 +17,15 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"time"
 
 	_ "embed"
 
 	"github.com/finleap-connect/monoctl/internal/config"
 	"github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/k8s"
 	"github.com/finleap-connect/monoctl/internal/spinner"
 	domApi "github.com/finleap-connect/monoskope/pkg/api/domain"
 	cmdData "github.com/finleap-connect/monoskope/pkg/api/domain/commanddata"
@@ -47,6 +51,9 @@ type createClusterUseCase struct {
 	conn           *ggrpc.ClientConn
 	cHandlerClient esApi.CommandHandlerClient
 	clusterClient  domApi.ClusterClient
+	// bootstrapOptions are set if the onboarding manifests of the new cluster are rendered
+	bootstrapOptions *BootstrapOptions
+	out              io.Writer
 }
 
 func NewCreateClusterUseCase(config *config.Config, name, apiServerAddress string, caCertBundle []byte) UseCase {
@@ -55,10 +62,18 @@ func NewCreateClusterUseCase(config *config.Config, name, apiServerAddress strin
 		name:             name,
 		apiServerAddress: apiServerAddress,
 		caCertBundle:     caCertBundle,
+		out:              os.Stdout,
 	}
 	return useCase
 }
 
+// NewCreateClusterWithBootstrapUseCase returns the use-case creating the cluster and rendering its onboarding manifests
+func NewCreateClusterWithBootstrapUseCase(config *config.Config, name, apiServerAddress string, caCertBundle []byte, bootstrapOptions *BootstrapOptions) UseCase {
+	useCase := NewCreateClusterUseCase(config, name, apiServerAddress, caCertBundle).(*createClusterUseCase)
+	useCase.bootstrapOptions = bootstrapOptions
+	return useCase
+}
+
 func (u *createClusterUseCase) setUp(ctx context.Context) error {
 	var err error
 
@@ -97,12 +112,32 @@ func (u *createClusterUseCase) doCreate(ctx context.Context) (*esApi.CommandRepl
 // Prepare some data to insert into the template.
 type ClusterRenderData struct {
 	ApiServerAddress string
-	M8Endpoint       string
-	Jwt              string
 	ClusterName      string
+	IssuerURL        string
+	ClientID         string
+	UsernameClaim    string
+	UsernamePrefix   string
+	GroupsClaim      string
+	RequiredClaim    string
+}
+
+// validateCABundle checks that the bundle consists of CA certificates only, writing a warning to w for any not valid now
+func validateCABundle(bundle []byte, w io.Writer) error {
+	certs, err := k8s.ParseCABundle(bundle)
+	if err != nil {
+		return err
+	}
+	for _, warning := range k8s.CertificateValidityWarnings(certs, time.Now()) {
+		fmt.Fprintf(w, "Warning: %s.\n", warning)
+	}
+	return nil
 }
 
 func (u *createClusterUseCase) Run(ctx context.Context) error {
+	if err := validateCABundle(u.caCertBundle, os.Stderr); err != nil {
+		return err
+	}
+
 	err := u.setUp(ctx)
 	if err != nil {
 		return err
@@ -110,5 +145,11 @@ func (u *createClusterUseCase) Run(ctx context.Context) error {
 	defer u.conn.Close()
 
 	_, err = u.doCreate(ctx)
-	return err
+	if err != nil || u.bootstrapOptions == nil {
+		return err
+	}
+	if err := bootstrapCluster(u.config, u.name, u.apiServerAddress, u.bootstrapOptions, u.out); err != nil {
+		return fmt.Errorf("cluster created, rendering its bootstrap manifests failed, retry with `monoctl get cluster-bootstrap %s`: %w", u.name, err)
+	}
+	return nil
 }
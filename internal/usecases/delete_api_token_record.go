@@ -0,0 +1,55 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+)
+
+// deleteAPITokenRecordUseCase provides the internal use-case of removing the local record of an API token.
+// The m8 control plane can not revoke API tokens, the token itself stays valid until it expires.
+type deleteAPITokenRecordUseCase struct {
+	useCaseBase
+	configManager *config.ClientConfigManager
+	id            string
+	out           io.Writer
+}
+
+// NewDeleteAPITokenRecordUseCase returns a use-case removing the local record of the API token with the given id.
+func NewDeleteAPITokenRecordUseCase(configManager *config.ClientConfigManager, id string) UseCase {
+	useCase := &deleteAPITokenRecordUseCase{
+		useCaseBase:   NewUseCaseBase("delete-api-token-record", configManager.GetConfig()),
+		configManager: configManager,
+		id:            id,
+		out:           os.Stdout,
+	}
+	return useCase
+}
+
+func (u *deleteAPITokenRecordUseCase) Run(ctx context.Context) error {
+	if !u.config.RemoveAPIToken(u.id) {
+		return fmt.Errorf("no record of API token '%s' found", u.id)
+	}
+	if err := u.configManager.SaveConfig(); err != nil {
+		return err
+	}
+	fmt.Fprintf(u.out, "Record of API token '%s' removed. The token has NOT been revoked and stays valid until it expires.\n", u.id)
+	return nil
+}
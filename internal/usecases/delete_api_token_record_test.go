@@ -0,0 +1,77 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/google/uuid"
+	testutil_fs "github.com/kubism/testutil/pkg/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/zalando/go-keyring"
+)
+
+var _ = Describe("DeleteAPITokenRecord", func() {
+	var (
+		ctx            = context.Background()
+		fakeConfigData = `server: https://1.1.1.1`
+	)
+
+	newConfigManager := func(tokenId string) (*config.ClientConfigManager, func()) {
+		keyring.MockInit()
+
+		tempFile, err := testutil_fs.NewTempFile([]byte(fakeConfigData))
+		Expect(err).NotTo(HaveOccurred())
+
+		confManager := config.NewLoaderFromExplicitFile(tempFile.Path)
+		Expect(confManager.LoadConfig()).NotTo(HaveOccurred())
+		confManager.GetConfig().AddAPIToken(&config.APITokenInformation{
+			Id:      tokenId,
+			User:    "some-user",
+			Created: time.Now(),
+			Expiry:  time.Now().Add(1 * time.Hour),
+		})
+
+		return confManager, func() { tempFile.Close() }
+	}
+
+	It("should remove the record and state that the token is not revoked", func() {
+		tokenId := uuid.New().String()
+		confManager, cleanup := newConfigManager(tokenId)
+		defer cleanup()
+
+		uc := NewDeleteAPITokenRecordUseCase(confManager, tokenId).(*deleteAPITokenRecordUseCase)
+		buf := new(bytes.Buffer)
+		uc.out = buf
+
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("has NOT been revoked"))
+		Expect(confManager.LoadConfig()).To(Succeed())
+		Expect(confManager.GetConfig().GetAPIToken(tokenId)).To(BeNil())
+	})
+
+	It("should fail for unknown records", func() {
+		confManager, cleanup := newConfigManager(uuid.New().String())
+		defer cleanup()
+
+		err := NewDeleteAPITokenRecordUseCase(confManager, "unknown").Run(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("no record of API token 'unknown' found"))
+	})
+})
@@ -0,0 +1,262 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/finleap-connect/monoctl/internal/output"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	esApi "github.com/finleap-connect/monoskope/pkg/api/eventsourcing"
+	cmd "github.com/finleap-connect/monoskope/pkg/domain/commands"
+	commandTypes "github.com/finleap-connect/monoskope/pkg/domain/constants/commands"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/scopes"
+	es "github.com/finleap-connect/monoskope/pkg/eventsourcing"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	deleteKindRoleBinding   = "rolebinding"
+	deleteKindClusterAccess = "cluster access"
+)
+
+// DeleteOptions configures how dependents of a deleted tenant, cluster or user are handled
+type DeleteOptions struct {
+	// Cascade deletes rolebindings and tenant-cluster bindings depending on the deleted entity first
+	Cascade bool
+	// Confirm is asked to proceed after the preview, the deletion is not confirmed if nil
+	Confirm func(string) bool
+	// ResumeCommand is the command resuming the deletion after a partial failure
+	ResumeCommand string
+}
+
+// deleteTarget is a single entity removed by a delete command
+type deleteTarget struct {
+	kind        string
+	name        string
+	id          string
+	commandType es.CommandType
+	err         error
+	deleted     bool
+}
+
+// deletePlan is everything removed when deleting an entity, dependents first
+type deletePlan struct {
+	dependents []*deleteTarget
+	target     *deleteTarget
+}
+
+// targets returns the targets in the order they are deleted
+func (p *deletePlan) targets(cascade bool) []*deleteTarget {
+	if !cascade {
+		return []*deleteTarget{p.target}
+	}
+	return append(append([]*deleteTarget{}, p.dependents...), p.target)
+}
+
+// renderDeleteTargets writes the targets to out, with the result of their deletion if withResult is set
+func renderDeleteTargets(out io.Writer, targets []*deleteTarget, withResult bool) error {
+	header := []string{"#", "KIND", "NAME", "ID"}
+	if withResult {
+		header = append(header, "RESULT")
+	}
+
+	var data [][]interface{}
+	for i, target := range targets {
+		// the step keeps the order in which the targets are deleted
+		row := []interface{}{i + 1, target.kind, target.name, target.id}
+		if withResult {
+			switch {
+			case target.deleted:
+				row = append(row, "deleted")
+			case target.err != nil:
+				row = append(row, fmt.Sprintf("failed: %v", target.err))
+			default:
+				row = append(row, "pending")
+			}
+		}
+		data = append(data, row)
+	}
+
+	tbl, err := output.NewTableFactory().
+		SetOutput(out).
+		SetHeader(header).
+		SetData(data).
+		ToTable()
+	if err != nil {
+		return err
+	}
+	tbl.Render()
+	return nil
+}
+
+// executeDeletePlan previews the plan, asks for confirmation and deletes the targets.
+// The target itself is only deleted if all its dependents have been deleted. Running
+// the same delete again resumes a partially failed deletion as the plan only contains
+// what has not been deleted yet. Returns false if the user cancelled the deletion.
+func executeDeletePlan(ctx context.Context, cmdHandlerClient esApi.CommandHandlerClient, plan *deletePlan, options *DeleteOptions, out io.Writer) (bool, error) {
+	targets := plan.targets(options.Cascade)
+
+	if len(plan.dependents) > 0 && !options.Cascade {
+		fmt.Fprintf(out, "The following depends on %s '%s' and is left behind, use --cascade to delete it as well:\n", plan.target.kind, plan.target.name)
+		if err := renderDeleteTargets(out, plan.dependents, false); err != nil {
+			return false, err
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintf(out, "The following will be deleted:\n")
+	if err := renderDeleteTargets(out, targets, false); err != nil {
+		return false, err
+	}
+
+	if options.Confirm != nil {
+		question := fmt.Sprintf("Delete %s '%s'", plan.target.kind, plan.target.name)
+		if len(targets) > 1 {
+			question = fmt.Sprintf("%s and %d dependents", question, len(targets)-1)
+		}
+		if !options.Confirm(question) {
+			fmt.Fprintln(out, "Deletion cancelled.")
+			return false, nil
+		}
+	}
+
+	var failed int
+	for _, target := range targets {
+		if target == plan.target && failed > 0 {
+			break
+		}
+		_, err := cmdHandlerClient.Execute(ctx, cmd.NewCommand(uuid.MustParse(target.id), target.commandType))
+		if err != nil {
+			target.err = err
+			failed++
+			continue
+		}
+		target.deleted = true
+	}
+
+	if failed == 0 {
+		return true, nil
+	}
+
+	fmt.Fprintf(out, "\nDeletion of %s '%s' incomplete:\n", plan.target.kind, plan.target.name)
+	if err := renderDeleteTargets(out, targets, true); err != nil {
+		return false, err
+	}
+	if options.ResumeCommand != "" {
+		fmt.Fprintf(out, "Run `%s` to resume.\n", options.ResumeCommand)
+	}
+	return false, fmt.Errorf("failed deleting %d of %d entities", failed, len(targets))
+}
+
+// roleBindingTarget returns the target deleting the rolebinding of the user
+func roleBindingTarget(user, resource string, rb *projections.UserRoleBinding) *deleteTarget {
+	name := fmt.Sprintf("%s of %s", rb.Role, user)
+	if resource != "" {
+		name = fmt.Sprintf("%s on %s '%s'", name, rb.Scope, resource)
+	}
+	return &deleteTarget{
+		kind:        deleteKindRoleBinding,
+		name:        name,
+		id:          rb.Id,
+		commandType: commandTypes.DeleteUserRoleBinding,
+	}
+}
+
+// clusterAccessTarget returns the target deleting the tenant-cluster binding
+func clusterAccessTarget(tenant, cluster string, binding *projections.TenantClusterBinding) *deleteTarget {
+	return &deleteTarget{
+		kind:        deleteKindClusterAccess,
+		name:        fmt.Sprintf("tenant '%s' on cluster '%s'", tenant, cluster),
+		id:          binding.Id,
+		commandType: commandTypes.DeleteTenantClusterBinding,
+	}
+}
+
+// tenantNameOf returns the name of the tenant, or the id if it can not be looked up as it might be deleted already
+func tenantNameOf(ctx context.Context, tenantClient api.TenantClient, id string) string {
+	if tenant, err := tenantClient.GetById(ctx, wrapperspb.String(id)); err == nil {
+		return tenant.Name
+	}
+	return id
+}
+
+// clusterNameOf returns the name of the cluster, or the id if it can not be looked up as it might be deleted already
+func clusterNameOf(ctx context.Context, clusterClient api.ClusterClient, id string) string {
+	if cluster, err := clusterClient.GetById(ctx, wrapperspb.String(id)); err == nil {
+		return cluster.Name
+	}
+	return id
+}
+
+// tenantRoleBindings returns the rolebindings of the members of the tenant which are scoped to it
+func tenantRoleBindings(ctx context.Context, tenantClient api.TenantClient, userClient api.UserClient, tenant *projections.Tenant) ([]*deleteTarget, error) {
+	members, err := tenantClient.GetUsers(ctx, wrapperspb.String(tenant.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*deleteTarget
+	for {
+		// Read next
+		member, err := members.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		roleBindings, err := userRoleBindings(ctx, userClient, member.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, rb := range roleBindings {
+			if rb.Scope == string(scopes.Tenant) && rb.Resource == tenant.Id {
+				targets = append(targets, roleBindingTarget(member.Email, tenant.Name, rb))
+			}
+		}
+	}
+	return targets, nil
+}
+
+// userRoleBindings returns all rolebindings of the user
+func userRoleBindings(ctx context.Context, userClient api.UserClient, userId string) ([]*projections.UserRoleBinding, error) {
+	stream, err := userClient.GetRoleBindingsById(ctx, wrapperspb.String(userId))
+	if err != nil {
+		return nil, err
+	}
+
+	var roleBindings []*projections.UserRoleBinding
+	for {
+		// Read next
+		rb, err := stream.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+		roleBindings = append(roleBindings, rb)
+	}
+	return roleBindings, nil
+}
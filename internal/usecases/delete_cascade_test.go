@@ -0,0 +1,238 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mdomain "github.com/finleap-connect/monoctl/test/mock/domain"
+	mEventSourcing "github.com/finleap-connect/monoctl/test/mock/eventsourcing"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	cmd "github.com/finleap-connect/monoskope/pkg/domain/commands"
+	commandTypes "github.com/finleap-connect/monoskope/pkg/domain/constants/commands"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/roles"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/scopes"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var _ = Describe("DeleteCascade", func() {
+	var (
+		mockCtrl *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	var (
+		ctx  = context.Background()
+		conf = config.NewConfig()
+
+		expectedTenant = &projections.Tenant{
+			Id:   uuid.New().String(),
+			Name: "the-tenant",
+		}
+		expectedCluster = &projections.Cluster{
+			Id:   uuid.New().String(),
+			Name: "the-cluster",
+		}
+		expectedUser = &projections.User{
+			Id:    uuid.New().String(),
+			Name:  "Jane Doe",
+			Email: "jane.doe@monoskope.io",
+		}
+		expectedRoleBinding = &projections.UserRoleBinding{
+			Id:       uuid.New().String(),
+			UserId:   expectedUser.Id,
+			Role:     string(roles.Admin),
+			Scope:    string(scopes.Tenant),
+			Resource: expectedTenant.Id,
+		}
+		expectedMapping = &projections.TenantClusterBinding{
+			Id:        uuid.New().String(),
+			ClusterId: expectedCluster.Id,
+			TenantId:  expectedTenant.Id,
+		}
+	)
+
+	// newDeleteTenantUseCase returns the use-case deleting the tenant having a member and access to a cluster
+	newDeleteTenantUseCase := func(options *DeleteOptions) (*deleteTenantUseCase, *mEventSourcing.MockCommandHandlerClient) {
+		mockTenantClient := mdomain.NewMockTenantClient(mockCtrl)
+		mockUserClient := mdomain.NewMockUserClient(mockCtrl)
+		mockClusterClient := mdomain.NewMockClusterClient(mockCtrl)
+		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
+		mockCmdHandlerClient := mEventSourcing.NewMockCommandHandlerClient(mockCtrl)
+
+		mockTenantClient.EXPECT().GetByName(ctx, wrapperspb.String(expectedTenant.Name)).Return(expectedTenant, nil)
+		usersClient := mdomain.NewMockTenant_GetUsersClient(mockCtrl)
+		usersClient.EXPECT().Recv().Return(&projections.TenantUser{Id: expectedUser.Id, Email: expectedUser.Email, TenantId: expectedTenant.Id}, nil)
+		usersClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockTenantClient.EXPECT().GetUsers(ctx, wrapperspb.String(expectedTenant.Id)).Return(usersClient, nil)
+
+		roleBindingsClient := mdomain.NewMockUser_GetRoleBindingsByIdClient(mockCtrl)
+		roleBindingsClient.EXPECT().Recv().Return(&projections.UserRoleBinding{
+			Id:     uuid.New().String(),
+			UserId: expectedUser.Id,
+			Role:   string(roles.User),
+			Scope:  string(scopes.System),
+		}, nil)
+		roleBindingsClient.EXPECT().Recv().Return(expectedRoleBinding, nil)
+		roleBindingsClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockUserClient.EXPECT().GetRoleBindingsById(ctx, wrapperspb.String(expectedUser.Id)).Return(roleBindingsClient, nil)
+
+		mappingsClient := mdomain.NewMockClusterAccess_GetTenantClusterMappingsByTenantIdClient(mockCtrl)
+		mappingsClient.EXPECT().Recv().Return(expectedMapping, nil)
+		mappingsClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterAccessClient.EXPECT().GetTenantClusterMappingsByTenantId(ctx, wrapperspb.String(expectedTenant.Id)).Return(mappingsClient, nil)
+		mockClusterClient.EXPECT().GetById(ctx, wrapperspb.String(expectedCluster.Id)).Return(expectedCluster, nil)
+
+		uc := NewDeleteTenantUseCase(conf, expectedTenant.Name, options).(*deleteTenantUseCase)
+		uc.tenantClient = mockTenantClient
+		uc.userClient = mockUserClient
+		uc.clusterClient = mockClusterClient
+		uc.clusterAccessClient = mockClusterAccessClient
+		uc.cmdHandlerClient = mockCmdHandlerClient
+		uc.out = new(bytes.Buffer)
+		uc.setInitialized()
+
+		return uc, mockCmdHandlerClient
+	}
+
+	It("deletes the dependents of a tenant first", func() {
+		var question string
+		uc, mockCmdHandlerClient := newDeleteTenantUseCase(&DeleteOptions{
+			Cascade: true,
+			Confirm: func(msg string) bool {
+				question = msg
+				return true
+			},
+		})
+
+		gomock.InOrder(
+			mockCmdHandlerClient.EXPECT().Execute(ctx, cmd.NewCommand(uuid.MustParse(expectedRoleBinding.Id), commandTypes.DeleteUserRoleBinding)),
+			mockCmdHandlerClient.EXPECT().Execute(ctx, cmd.NewCommand(uuid.MustParse(expectedMapping.Id), commandTypes.DeleteTenantClusterBinding)),
+			mockCmdHandlerClient.EXPECT().Execute(ctx, cmd.NewCommand(uuid.MustParse(expectedTenant.Id), commandTypes.DeleteTenant)),
+		)
+
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(question).To(Equal("Delete tenant 'the-tenant' and 2 dependents"))
+		out := uc.out.(*bytes.Buffer).String()
+		Expect(out).To(MatchRegexp(`(?m)^2\s+cluster access\s+.*the-cluster.*\s+` + expectedMapping.Id + `\s*$`))
+		Expect(out).To(MatchRegexp(`(?m)^3\s+tenant\s+the-tenant\s+` + expectedTenant.Id + `\s*$`))
+		Expect(out).To(ContainSubstring("Tenant 'the-tenant' deleted."))
+	})
+	It("leaves dependents behind without cascade", func() {
+		uc, mockCmdHandlerClient := newDeleteTenantUseCase(nil)
+
+		mockCmdHandlerClient.EXPECT().Execute(ctx, cmd.NewCommand(uuid.MustParse(expectedTenant.Id), commandTypes.DeleteTenant))
+
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(uc.out.(*bytes.Buffer).String()).To(ContainSubstring("use --cascade"))
+	})
+	It("deletes nothing if not confirmed", func() {
+		uc, _ := newDeleteTenantUseCase(&DeleteOptions{
+			Cascade: true,
+			Confirm: func(string) bool { return false },
+		})
+
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(uc.out.(*bytes.Buffer).String()).To(ContainSubstring("Deletion cancelled."))
+	})
+	It("keeps the tenant and reports how to resume if a dependent fails", func() {
+		uc, mockCmdHandlerClient := newDeleteTenantUseCase(&DeleteOptions{
+			Cascade:       true,
+			ResumeCommand: "monoctl delete tenant the-tenant --yes --cascade",
+		})
+
+		gomock.InOrder(
+			mockCmdHandlerClient.EXPECT().Execute(ctx, cmd.NewCommand(uuid.MustParse(expectedRoleBinding.Id), commandTypes.DeleteUserRoleBinding)).Return(nil, errors.New("permission denied")),
+			mockCmdHandlerClient.EXPECT().Execute(ctx, cmd.NewCommand(uuid.MustParse(expectedMapping.Id), commandTypes.DeleteTenantClusterBinding)),
+		)
+
+		err := uc.Run(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("failed deleting 1 of 3 entities"))
+		Expect(uc.out.(*bytes.Buffer).String()).To(MatchRegexp(`(?m)^1\s+rolebinding\s+.*\s+` + expectedRoleBinding.Id + `\s+failed: permission denied\s*$`))
+		Expect(uc.out.(*bytes.Buffer).String()).To(ContainSubstring("Run `monoctl delete tenant the-tenant --yes --cascade` to resume."))
+	})
+	It("deletes the rolebindings of a user", func() {
+		mockUserClient := mdomain.NewMockUserClient(mockCtrl)
+		mockTenantClient := mdomain.NewMockTenantClient(mockCtrl)
+		mockCmdHandlerClient := mEventSourcing.NewMockCommandHandlerClient(mockCtrl)
+
+		mockUserClient.EXPECT().GetByEmail(ctx, wrapperspb.String(expectedUser.Email)).Return(expectedUser, nil)
+		roleBindingsClient := mdomain.NewMockUser_GetRoleBindingsByIdClient(mockCtrl)
+		roleBindingsClient.EXPECT().Recv().Return(expectedRoleBinding, nil)
+		roleBindingsClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockUserClient.EXPECT().GetRoleBindingsById(ctx, wrapperspb.String(expectedUser.Id)).Return(roleBindingsClient, nil)
+		mockTenantClient.EXPECT().GetById(ctx, wrapperspb.String(expectedTenant.Id)).Return(expectedTenant, nil)
+
+		gomock.InOrder(
+			mockCmdHandlerClient.EXPECT().Execute(ctx, cmd.NewCommand(uuid.MustParse(expectedRoleBinding.Id), commandTypes.DeleteUserRoleBinding)),
+			mockCmdHandlerClient.EXPECT().Execute(ctx, cmd.NewCommand(uuid.MustParse(expectedUser.Id), commandTypes.DeleteUser)),
+		)
+
+		uc := NewDeleteUserUseCase(conf, expectedUser.Email, &DeleteOptions{Cascade: true}).(*deleteUserUseCase)
+		uc.userClient = mockUserClient
+		uc.tenantClient = mockTenantClient
+		uc.cmdHandlerClient = mockCmdHandlerClient
+		uc.out = new(bytes.Buffer)
+		uc.setInitialized()
+
+		plan, err := uc.plan(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.dependents).To(HaveLen(1))
+		Expect(plan.dependents[0].name).To(Equal("admin of jane.doe@monoskope.io on tenant 'the-tenant'"))
+
+		deleted, err := executeDeletePlan(ctx, uc.cmdHandlerClient, plan, uc.options, uc.out)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleted).To(BeTrue())
+	})
+	It("falls back to the id of a tenant which can not be looked up", func() {
+		mockClusterClient := mdomain.NewMockClusterClient(mockCtrl)
+		mockTenantClient := mdomain.NewMockTenantClient(mockCtrl)
+		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
+
+		mockClusterClient.EXPECT().GetByName(ctx, wrapperspb.String(expectedCluster.Name)).Return(expectedCluster, nil)
+		mappingsClient := mdomain.NewMockClusterAccess_GetTenantClusterMappingsByClusterIdClient(mockCtrl)
+		mappingsClient.EXPECT().Recv().Return(expectedMapping, nil)
+		mappingsClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterAccessClient.EXPECT().GetTenantClusterMappingsByClusterId(ctx, wrapperspb.String(expectedCluster.Id)).Return(mappingsClient, nil)
+		mockTenantClient.EXPECT().GetById(ctx, wrapperspb.String(expectedTenant.Id)).Return(nil, errors.New("not found"))
+
+		uc := NewDeleteClusterUseCase(conf, expectedCluster.Name, nil).(*deleteClusterUseCase)
+		uc.clusterClient = mockClusterClient
+		uc.tenantClient = mockTenantClient
+		uc.clusterAccessClient = mockClusterAccessClient
+		uc.setInitialized()
+
+		plan, err := uc.plan(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.dependents).To(HaveLen(1))
+		Expect(plan.dependents[0].name).To(Equal("tenant '" + expectedTenant.Id + "' on cluster 'the-cluster'"))
+	})
+})
@@ -17,56 +17,113 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/finleap-connect/monoctl/internal/config"
-	"github.com/finleap-connect/monoctl/internal/grpc"
-	"github.com/finleap-connect/monoctl/internal/spinner"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
 	api "github.com/finleap-connect/monoskope/pkg/api/domain"
 	esApi "github.com/finleap-connect/monoskope/pkg/api/eventsourcing"
-	cmd "github.com/finleap-connect/monoskope/pkg/domain/commands"
 	commandTypes "github.com/finleap-connect/monoskope/pkg/domain/constants/commands"
-	"github.com/google/uuid"
-	"golang.org/x/oauth2"
+	ggrpc "google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type deleteClusterUseCase struct {
 	useCaseBase
-	name string
+	conn                *ggrpc.ClientConn
+	clusterClient       api.ClusterClient
+	tenantClient        api.TenantClient
+	clusterAccessClient api.ClusterAccessClient
+	cmdHandlerClient    esApi.CommandHandlerClient
+	name                string
+	options             *DeleteOptions
+	out                 io.Writer
 }
 
-func NewDeleteClusterUseCase(config *config.Config, name string) UseCase {
+func NewDeleteClusterUseCase(config *config.Config, name string, options *DeleteOptions) UseCase {
+	if options == nil {
+		options = &DeleteOptions{}
+	}
 	useCase := &deleteClusterUseCase{
 		useCaseBase: NewUseCaseBase("delete-cluster", config),
 		name:        name,
+		options:     options,
+		out:         os.Stdout,
 	}
 	return useCase
 }
 
-func (u *deleteClusterUseCase) Run(ctx context.Context) error {
-	s := spinner.NewSpinner()
-	defer s.Stop()
+func (u *deleteClusterUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
 
-	conn, err := grpc.CreateGrpcConnectionAuthenticated(ctx, u.config.Server, &oauth2.Token{AccessToken: u.config.AuthInformation.Token})
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
 
-	clusterClient := api.NewClusterClient(conn)
-	cluster, err := clusterClient.GetByName(ctx, wrapperspb.String(u.name))
+	u.conn = conn
+	u.clusterClient = api.NewClusterClient(u.conn)
+	u.tenantClient = api.NewTenantClient(u.conn)
+	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
+	u.cmdHandlerClient = esApi.NewCommandHandlerClient(u.conn)
+	u.setInitialized()
+
+	return nil
+}
+
+// plan collects the tenants having access to the cluster
+func (u *deleteClusterUseCase) plan(ctx context.Context) (*deletePlan, error) {
+	cluster, err := u.clusterClient.GetByName(ctx, wrapperspb.String(u.name))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	command := cmd.NewCommand(uuid.MustParse(cluster.Id), commandTypes.DeleteCluster)
-	cmdHandlerClient := esApi.NewCommandHandlerClient(conn)
-	_, err = cmdHandlerClient.Execute(ctx, command)
+	plan := &deletePlan{
+		target: &deleteTarget{kind: "cluster", name: cluster.Name, id: cluster.Id, commandType: commandTypes.DeleteCluster},
+	}
+
+	mappings, err := u.clusterAccessClient.GetTenantClusterMappingsByClusterId(ctx, wrapperspb.String(cluster.Id))
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// Read next
+		mapping, err := mappings.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
 
-	s.Stop()
-	if err == nil {
-		fmt.Printf("Cluster '%s' deleted.\n", u.name)
+		plan.dependents = append(plan.dependents, clusterAccessTarget(tenantNameOf(ctx, u.tenantClient, mapping.TenantId), cluster.Name, mapping))
 	}
 
+	return plan, nil
+}
+
+func (u *deleteClusterUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	plan, err := u.plan(ctx)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := executeDeletePlan(ctx, u.cmdHandlerClient, plan, u.options, u.out)
+	if deleted {
+		fmt.Fprintf(u.out, "Cluster '%s' deleted.\n", u.name)
+	}
 	return err
 }
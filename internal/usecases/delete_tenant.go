@@ -17,56 +17,120 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/finleap-connect/monoctl/internal/config"
-	"github.com/finleap-connect/monoctl/internal/grpc"
-	"github.com/finleap-connect/monoctl/internal/spinner"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
 	api "github.com/finleap-connect/monoskope/pkg/api/domain"
 	esApi "github.com/finleap-connect/monoskope/pkg/api/eventsourcing"
-	cmd "github.com/finleap-connect/monoskope/pkg/domain/commands"
 	commandTypes "github.com/finleap-connect/monoskope/pkg/domain/constants/commands"
-	"github.com/google/uuid"
-	"golang.org/x/oauth2"
+	ggrpc "google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type deleteTenantUseCase struct {
 	useCaseBase
-	name string
+	conn                *ggrpc.ClientConn
+	tenantClient        api.TenantClient
+	userClient          api.UserClient
+	clusterClient       api.ClusterClient
+	clusterAccessClient api.ClusterAccessClient
+	cmdHandlerClient    esApi.CommandHandlerClient
+	name                string
+	options             *DeleteOptions
+	out                 io.Writer
 }
 
-func NewDeleteTenantUseCase(config *config.Config, name string) UseCase {
+func NewDeleteTenantUseCase(config *config.Config, name string, options *DeleteOptions) UseCase {
+	if options == nil {
+		options = &DeleteOptions{}
+	}
 	useCase := &deleteTenantUseCase{
 		useCaseBase: NewUseCaseBase("delete-tenant", config),
 		name:        name,
+		options:     options,
+		out:         os.Stdout,
 	}
 	return useCase
 }
 
-func (u *deleteTenantUseCase) Run(ctx context.Context) error {
-	s := spinner.NewSpinner()
-	defer s.Stop()
+func (u *deleteTenantUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
 
-	conn, err := grpc.CreateGrpcConnectionAuthenticated(ctx, u.config.Server, &oauth2.Token{AccessToken: u.config.AuthInformation.Token})
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
 
-	tenantClient := api.NewTenantClient(conn)
-	tenant, err := tenantClient.GetByName(ctx, wrapperspb.String(u.name))
+	u.conn = conn
+	u.tenantClient = api.NewTenantClient(u.conn)
+	u.userClient = api.NewUserClient(u.conn)
+	u.clusterClient = api.NewClusterClient(u.conn)
+	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
+	u.cmdHandlerClient = esApi.NewCommandHandlerClient(u.conn)
+	u.setInitialized()
+
+	return nil
+}
+
+// plan collects the rolebindings scoped to the tenant and its cluster access
+func (u *deleteTenantUseCase) plan(ctx context.Context) (*deletePlan, error) {
+	tenant, err := u.tenantClient.GetByName(ctx, wrapperspb.String(u.name))
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	plan := &deletePlan{
+		target: &deleteTarget{kind: "tenant", name: tenant.Name, id: tenant.Id, commandType: commandTypes.DeleteTenant},
+	}
+
+	plan.dependents, err = tenantRoleBindings(ctx, u.tenantClient, u.userClient, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings, err := u.clusterAccessClient.GetTenantClusterMappingsByTenantId(ctx, wrapperspb.String(tenant.Id))
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// Read next
+		mapping, err := mappings.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		plan.dependents = append(plan.dependents, clusterAccessTarget(tenant.Name, clusterNameOf(ctx, u.clusterClient, mapping.ClusterId), mapping))
 	}
 
-	command := cmd.NewCommand(uuid.MustParse(tenant.Id), commandTypes.DeleteTenant)
-	cmdHandlerClient := esApi.NewCommandHandlerClient(conn)
-	_, err = cmdHandlerClient.Execute(ctx, command)
+	return plan, nil
+}
+
+func (u *deleteTenantUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
 
-	s.Stop()
-	if err == nil {
-		fmt.Printf("Tenant '%s' deleted.\n", u.name)
+	plan, err := u.plan(ctx)
+	if err != nil {
+		return err
 	}
 
+	deleted, err := executeDeletePlan(ctx, u.cmdHandlerClient, plan, u.options, u.out)
+	if deleted {
+		fmt.Fprintf(u.out, "Tenant '%s' deleted.\n", u.name)
+	}
 	return err
 }
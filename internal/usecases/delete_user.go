@@ -17,56 +17,105 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/finleap-connect/monoctl/internal/config"
-	"github.com/finleap-connect/monoctl/internal/grpc"
-	"github.com/finleap-connect/monoctl/internal/spinner"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
 	api "github.com/finleap-connect/monoskope/pkg/api/domain"
 	esApi "github.com/finleap-connect/monoskope/pkg/api/eventsourcing"
-	cmd "github.com/finleap-connect/monoskope/pkg/domain/commands"
 	commandTypes "github.com/finleap-connect/monoskope/pkg/domain/constants/commands"
-	"github.com/google/uuid"
-	"golang.org/x/oauth2"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/scopes"
+	ggrpc "google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type deleteUserUseCase struct {
 	useCaseBase
-	email string
+	conn             *ggrpc.ClientConn
+	userClient       api.UserClient
+	tenantClient     api.TenantClient
+	cmdHandlerClient esApi.CommandHandlerClient
+	email            string
+	options          *DeleteOptions
+	out              io.Writer
 }
 
-func NewDeleteUserUseCase(config *config.Config, email string) UseCase {
+func NewDeleteUserUseCase(config *config.Config, email string, options *DeleteOptions) UseCase {
+	if options == nil {
+		options = &DeleteOptions{}
+	}
 	useCase := &deleteUserUseCase{
 		useCaseBase: NewUseCaseBase("delete-user", config),
 		email:       email,
+		options:     options,
+		out:         os.Stdout,
 	}
 	return useCase
 }
 
-func (u *deleteUserUseCase) Run(ctx context.Context) error {
-	s := spinner.NewSpinner()
-	defer s.Stop()
+func (u *deleteUserUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
 
-	conn, err := grpc.CreateGrpcConnectionAuthenticated(ctx, u.config.Server, &oauth2.Token{AccessToken: u.config.AuthInformation.Token})
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
 
-	userClient := api.NewUserClient(conn)
-	user, err := userClient.GetByEmail(ctx, wrapperspb.String(u.email))
+	u.conn = conn
+	u.userClient = api.NewUserClient(u.conn)
+	u.tenantClient = api.NewTenantClient(u.conn)
+	u.cmdHandlerClient = esApi.NewCommandHandlerClient(u.conn)
+	u.setInitialized()
+
+	return nil
+}
+
+// plan collects the rolebindings of the user
+func (u *deleteUserUseCase) plan(ctx context.Context) (*deletePlan, error) {
+	user, err := u.userClient.GetByEmail(ctx, wrapperspb.String(u.email))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	command := cmd.NewCommand(uuid.MustParse(user.Id), commandTypes.DeleteUser)
-	cmdHandlerClient := esApi.NewCommandHandlerClient(conn)
-	_, err = cmdHandlerClient.Execute(ctx, command)
+	plan := &deletePlan{
+		target: &deleteTarget{kind: "user", name: user.Email, id: user.Id, commandType: commandTypes.DeleteUser},
+	}
 
-	s.Stop()
-	if err == nil {
-		fmt.Printf("User '%s' deleted.\n", u.email)
+	roleBindings, err := userRoleBindings(ctx, u.userClient, user.Id)
+	if err != nil {
+		return nil, err
 	}
+	for _, rb := range roleBindings {
+		resource := rb.Resource
+		if rb.Scope == string(scopes.Tenant) {
+			resource = tenantNameOf(ctx, u.tenantClient, rb.Resource)
+		}
+		plan.dependents = append(plan.dependents, roleBindingTarget(user.Email, resource, rb))
+	}
+
+	return plan, nil
+}
 
+func (u *deleteUserUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	plan, err := u.plan(ctx)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := executeDeletePlan(ctx, u.cmdHandlerClient, plan, u.options, u.out)
+	if deleted {
+		fmt.Fprintf(u.out, "User '%s' deleted.\n", u.email)
+	}
 	return err
 }
@@ -0,0 +1,167 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/output"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// describeMetadata is the lifecycle metadata of a described entity with the names of the users who changed it
+type describeMetadata struct {
+	Created        *time.Time `json:"created,omitempty"`
+	CreatedBy      string     `json:"createdBy,omitempty"`
+	LastModified   *time.Time `json:"lastModified,omitempty"`
+	LastModifiedBy string     `json:"lastModifiedBy,omitempty"`
+	Deleted        *time.Time `json:"deleted,omitempty"`
+	DeletedBy      string     `json:"deletedBy,omitempty"`
+}
+
+// userNames resolves the names of users by their id, looking up each user once
+type userNames struct {
+	userClient api.UserClient
+	names      map[string]string
+}
+
+func newUserNames(userClient api.UserClient) *userNames {
+	return &userNames{userClient: userClient, names: make(map[string]string)}
+}
+
+// get returns the name of the user or the id if the user can not be found
+func (n *userNames) get(ctx context.Context, id string) string {
+	if id == "" {
+		return ""
+	}
+	if name, ok := n.names[id]; ok {
+		return name
+	}
+	name := id
+	if user, err := n.userClient.GetById(ctx, wrapperspb.String(id)); err == nil {
+		name = user.Name
+	}
+	n.names[id] = name
+	return name
+}
+
+// describeTime returns the time of the timestamp or nil if it is not set
+func describeTime(timestamp *timestamppb.Timestamp) *time.Time {
+	if timestamp == nil || timestamp.AsTime().Unix() <= 0 {
+		return nil
+	}
+	t := timestamp.AsTime()
+	return &t
+}
+
+func (n *userNames) metadata(ctx context.Context, metadata *projections.LifecycleMetadata) *describeMetadata {
+	if metadata == nil {
+		return &describeMetadata{}
+	}
+	result := &describeMetadata{
+		Created:      describeTime(metadata.Created),
+		CreatedBy:    n.get(ctx, metadata.CreatedById),
+		LastModified: describeTime(metadata.LastModified),
+		Deleted:      describeTime(metadata.Deleted),
+	}
+	if result.LastModified != nil {
+		result.LastModifiedBy = n.get(ctx, metadata.LastModifiedById)
+	}
+	if result.Deleted != nil {
+		result.DeletedBy = n.get(ctx, metadata.DeletedById)
+	}
+	return result
+}
+
+// formatDescribeTime formats the time in the local timezone
+func formatDescribeTime(t *time.Time) string {
+	if t == nil {
+		return "<none>"
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+// formatDescribeChange formats when and by whom an entity has been changed
+func formatDescribeChange(t *time.Time, by string) string {
+	if t == nil || by == "" {
+		return formatDescribeTime(t)
+	}
+	return fmt.Sprintf("%s by %s", formatDescribeTime(t), by)
+}
+
+// describeField is a single line of the fields of a described entity
+type describeField struct {
+	name  string
+	value string
+}
+
+// metadataFields returns the fields showing the lifecycle metadata
+func metadataFields(metadata *describeMetadata) []describeField {
+	fields := []describeField{
+		{"Created", formatDescribeChange(metadata.Created, metadata.CreatedBy)},
+		{"Last Modified", formatDescribeChange(metadata.LastModified, metadata.LastModifiedBy)},
+	}
+	if metadata.Deleted != nil {
+		fields = append(fields, describeField{"Deleted", formatDescribeChange(metadata.Deleted, metadata.DeletedBy)})
+	}
+	return fields
+}
+
+// describeSection is a titled table of entities related to the described one
+type describeSection struct {
+	title  string
+	header []string
+	data   [][]interface{}
+}
+
+// renderDescription writes the fields aligned followed by the sections
+func renderDescription(out io.Writer, fields []describeField, sections []describeSection) error {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', 0)
+	for _, field := range fields {
+		fmt.Fprintf(w, "%s:\t%s\n", field.name, field.value)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return renderDescribeSections(out, sections)
+}
+
+// renderDescribeSections writes each section as table or <none> if it is empty
+func renderDescribeSections(out io.Writer, sections []describeSection) error {
+	for _, section := range sections {
+		fmt.Fprintf(out, "\n%s:\n", section.title)
+		if len(section.data) == 0 {
+			fmt.Fprintln(out, "<none>")
+			continue
+		}
+		tbl, err := output.NewTableFactory().
+			SetOutput(out).
+			SetHeader(section.header).
+			SetData(section.data).
+			ToTable()
+		if err != nil {
+			return err
+		}
+		tbl.Render()
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/jwt"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+)
+
+// describeAPITokenUseCase provides the internal use-case of decoding and validating an API token.
+// The signature is not verified, this is done by the m8 control plane whenever the token is used.
+type describeAPITokenUseCase struct {
+	useCaseBase
+	rawToken string
+	out      io.Writer
+}
+
+func NewDescribeAPITokenUseCase(config *config.Config, rawToken string) UseCase {
+	useCase := &describeAPITokenUseCase{
+		useCaseBase: NewUseCaseBase("describe-api-token", config),
+		rawToken:    strings.TrimSpace(rawToken),
+		out:         os.Stdout,
+	}
+	return useCase
+}
+
+func (u *describeAPITokenUseCase) Run(ctx context.Context) error {
+	claims, err := jwt.ParseUnverified(u.rawToken)
+	if err != nil {
+		return err
+	}
+	if claims.Claims == nil {
+		return errors.New("token does not contain any registered claims")
+	}
+
+	var problems []string
+	if !claims.IsAPIToken {
+		problems = append(problems, "not an API token")
+	}
+	if err := claims.ValidateWithLeeway(josejwt.Expected{Time: time.Now().UTC()}, 0); err != nil {
+		problems = append(problems, err.Error())
+	}
+	apiToken := u.config.GetAPIToken(claims.ID)
+
+	w := tabwriter.NewWriter(u.out, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", claims.ID)
+	fmt.Fprintf(w, "Issuer:\t%s\n", claims.Issuer)
+	fmt.Fprintf(w, "User ID:\t%s\n", claims.Subject)
+	if claims.StandardClaims != nil {
+		fmt.Fprintf(w, "Name:\t%s\n", claims.Name)
+		fmt.Fprintf(w, "Email:\t%s\n", claims.Email)
+	}
+	fmt.Fprintf(w, "Scopes:\t%s\n", strings.Join(strings.Fields(claims.Scope), ","))
+	fmt.Fprintf(w, "Issued:\t%s\n", formatNumericDate(claims.IssuedAt))
+	fmt.Fprintf(w, "Not before:\t%s\n", formatNumericDate(claims.NotBefore))
+	fmt.Fprintf(w, "Expiry:\t%s\n", formatNumericDate(claims.Expiry))
+	if claims.IssuedAt != nil && claims.Expiry != nil {
+		fmt.Fprintf(w, "Validity:\t%v\n", claims.Expiry.Time().Sub(claims.IssuedAt.Time()))
+	}
+	fmt.Fprintf(w, "Recorded locally:\t%v\n", apiToken != nil)
+	if len(problems) == 0 {
+		fmt.Fprintf(w, "Status:\tvalid\n")
+	} else {
+		fmt.Fprintf(w, "Status:\tinvalid\n")
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("token is invalid: %s", strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+func formatNumericDate(date *josejwt.NumericDate) string {
+	if date == nil {
+		return "<none>"
+	}
+	return date.Time().Local().Format(time.RFC3339)
+}
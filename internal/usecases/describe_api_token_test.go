@@ -0,0 +1,93 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mjwt "github.com/finleap-connect/monoskope/pkg/jwt"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+var _ = Describe("DescribeAPIToken", func() {
+	var (
+		ctx     = context.Background()
+		tokenId = uuid.New().String()
+		userId  = uuid.New().String()
+	)
+
+	newAPIToken := func(expiry time.Time) string {
+		return newTestToken(&mjwt.AuthToken{
+			Claims: &jwt.Claims{
+				ID:       tokenId,
+				Issuer:   "https://m8.example.com",
+				Subject:  userId,
+				IssuedAt: jwt.NewNumericDate(expiry.Add(-1 * time.Hour)),
+				Expiry:   jwt.NewNumericDate(expiry),
+			},
+			StandardClaims: &mjwt.StandardClaims{
+				Name: "some-user",
+			},
+			Scope:      "API WRITE_SCIM",
+			IsAPIToken: true,
+		})
+	}
+
+	It("should describe a valid token", func() {
+		conf := config.NewConfig()
+		uc := NewDescribeAPITokenUseCase(conf, newAPIToken(time.Now().Add(1*time.Hour))).(*describeAPITokenUseCase)
+		buf := new(bytes.Buffer)
+		uc.out = buf
+
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring(tokenId))
+		Expect(buf.String()).To(ContainSubstring(userId))
+		Expect(buf.String()).To(ContainSubstring("API,WRITE_SCIM"))
+		Expect(buf.String()).To(MatchRegexp(`Recorded locally:\s+false`))
+		Expect(buf.String()).To(MatchRegexp(`Status:\s+valid`))
+	})
+
+	It("should reject an expired token", func() {
+		conf := config.NewConfig()
+		uc := NewDescribeAPITokenUseCase(conf, newAPIToken(time.Now().Add(-1*time.Hour))).(*describeAPITokenUseCase)
+		uc.out = new(bytes.Buffer)
+
+		err := uc.Run(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("token is expired"))
+	})
+
+	It("should show if the token is recorded locally", func() {
+		conf := config.NewConfig()
+		conf.AddAPIToken(&config.APITokenInformation{Id: tokenId})
+		uc := NewDescribeAPITokenUseCase(conf, newAPIToken(time.Now().Add(1*time.Hour))).(*describeAPITokenUseCase)
+		buf := new(bytes.Buffer)
+		uc.out = buf
+
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(buf.String()).To(MatchRegexp(`Recorded locally:\s+true`))
+	})
+
+	It("should fail for garbage", func() {
+		uc := NewDescribeAPITokenUseCase(config.NewConfig(), "not-a-token")
+		Expect(uc.Run(ctx)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,214 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/k8s"
+	"github.com/finleap-connect/monoctl/internal/output"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type clusterDescription struct {
+	Id               string                      `json:"id"`
+	Name             string                      `json:"name"`
+	ApiServerAddress string                      `json:"apiServerAddress"`
+	CACertificates   []*caCertificateDescription `json:"caCertificates"`
+	CAError          string                      `json:"caError,omitempty"`
+	Metadata         *describeMetadata           `json:"metadata"`
+	Tenants          []*clusterTenantDescription `json:"tenants"`
+}
+
+type caCertificateDescription struct {
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	Fingerprint string    `json:"fingerprint"`
+	NotBefore   time.Time `json:"notBefore"`
+	NotAfter    time.Time `json:"notAfter"`
+}
+
+type clusterTenantDescription struct {
+	Id     string     `json:"id"`
+	Name   string     `json:"name"`
+	Prefix string     `json:"prefix"`
+	Since  *time.Time `json:"since,omitempty"`
+}
+
+// describeClusterUseCase provides the internal use-case of showing a cluster with the tenants having access to it.
+type describeClusterUseCase struct {
+	useCaseBase
+	conn                *ggrpc.ClientConn
+	clusterClient       api.ClusterClient
+	clusterAccessClient api.ClusterAccessClient
+	tenantClient        api.TenantClient
+	userClient          api.UserClient
+	name                string
+	outputFormat        output.OutputFormat
+	out                 io.Writer
+}
+
+func NewDescribeClusterUseCase(config *config.Config, name string, outputFormat output.OutputFormat) UseCase {
+	useCase := &describeClusterUseCase{
+		useCaseBase:  NewUseCaseBase("describe-cluster", config),
+		name:         name,
+		outputFormat: outputFormat,
+		out:          os.Stdout,
+	}
+	return useCase
+}
+
+func (u *describeClusterUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
+
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if err != nil {
+		return err
+	}
+
+	u.conn = conn
+	u.clusterClient = api.NewClusterClient(u.conn)
+	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
+	u.tenantClient = api.NewTenantClient(u.conn)
+	u.userClient = api.NewUserClient(u.conn)
+	u.setInitialized()
+
+	return nil
+}
+
+func (u *describeClusterUseCase) collect(ctx context.Context) (*clusterDescription, error) {
+	cluster, err := u.clusterClient.GetByName(ctx, wrapperspb.String(u.name))
+	if err != nil {
+		return nil, fmt.Errorf("failed getting cluster '%s': %w", u.name, err)
+	}
+
+	users := newUserNames(u.userClient)
+	result := &clusterDescription{
+		Id:               cluster.Id,
+		Name:             cluster.Name,
+		ApiServerAddress: cluster.ApiServerAddress,
+		CACertificates:   []*caCertificateDescription{},
+		Metadata:         users.metadata(ctx, cluster.Metadata),
+		Tenants:          []*clusterTenantDescription{},
+	}
+
+	certs, err := k8s.ParseCABundle(cluster.CaCertBundle)
+	if err != nil {
+		result.CAError = err.Error()
+	}
+	for _, cert := range certs {
+		result.CACertificates = append(result.CACertificates, &caCertificateDescription{
+			Subject:     cert.Subject.String(),
+			Issuer:      cert.Issuer.String(),
+			Fingerprint: k8s.CertificateFingerprint(cert),
+			NotBefore:   cert.NotBefore,
+			NotAfter:    cert.NotAfter,
+		})
+	}
+
+	mappings, err := u.clusterAccessClient.GetTenantClusterMappingsByClusterId(ctx, wrapperspb.String(cluster.Id))
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// Read next
+		mapping, err := mappings.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		tenant, err := u.tenantClient.GetById(ctx, wrapperspb.String(mapping.TenantId))
+		if err != nil {
+			return nil, err
+		}
+		tenantDescription := &clusterTenantDescription{
+			Id:     tenant.Id,
+			Name:   tenant.Name,
+			Prefix: tenant.Prefix,
+		}
+		if mapping.Metadata != nil {
+			tenantDescription.Since = describeTime(mapping.Metadata.Created)
+		}
+		result.Tenants = append(result.Tenants, tenantDescription)
+	}
+	sort.Slice(result.Tenants, func(i, j int) bool {
+		return result.Tenants[i].Name < result.Tenants[j].Name
+	})
+
+	return result, nil
+}
+
+func (u *describeClusterUseCase) render(result *clusterDescription) error {
+	if u.outputFormat == output.JSONFormat {
+		return output.WriteJSON(u.out, result)
+	}
+
+	fields := []describeField{
+		{"Name", result.Name},
+		{"ID", result.Id},
+		{"API Server Address", result.ApiServerAddress},
+	}
+	if result.CAError != "" {
+		fields = append(fields, describeField{"CA Bundle", fmt.Sprintf("invalid: %s", result.CAError)})
+	}
+	fields = append(fields, metadataFields(result.Metadata)...)
+
+	var certificates [][]interface{}
+	for _, cert := range result.CACertificates {
+		certificates = append(certificates, []interface{}{cert.Subject, cert.Fingerprint, formatDescribeTime(&cert.NotAfter)})
+	}
+	var tenants [][]interface{}
+	for _, tenant := range result.Tenants {
+		tenants = append(tenants, []interface{}{tenant.Name, tenant.Prefix, formatDescribeTime(tenant.Since)})
+	}
+
+	return renderDescription(u.out, fields, []describeSection{
+		{"CA Certificates", []string{"SUBJECT", "FINGERPRINT", "EXPIRES"}, certificates},
+		{"Tenants", []string{"NAME", "PREFIX", "SINCE"}, tenants},
+	})
+}
+
+func (u *describeClusterUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	result, err := u.collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.render(result)
+}
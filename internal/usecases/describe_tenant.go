@@ -0,0 +1,223 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/output"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type tenantDescription struct {
+	Id       string                      `json:"id"`
+	Name     string                      `json:"name"`
+	Prefix   string                      `json:"prefix"`
+	Metadata *describeMetadata           `json:"metadata"`
+	Members  []*tenantMemberDescription  `json:"members"`
+	Clusters []*tenantClusterDescription `json:"clusters"`
+}
+
+type tenantMemberDescription struct {
+	Id    string   `json:"id"`
+	Name  string   `json:"name"`
+	Email string   `json:"email"`
+	Roles []string `json:"roles"`
+}
+
+type tenantClusterDescription struct {
+	Id               string     `json:"id"`
+	Name             string     `json:"name"`
+	ApiServerAddress string     `json:"apiServerAddress"`
+	Since            *time.Time `json:"since,omitempty"`
+}
+
+// describeTenantUseCase provides the internal use-case of showing a tenant with its members and clusters.
+type describeTenantUseCase struct {
+	useCaseBase
+	conn                *ggrpc.ClientConn
+	tenantClient        api.TenantClient
+	clusterClient       api.ClusterClient
+	clusterAccessClient api.ClusterAccessClient
+	userClient          api.UserClient
+	name                string
+	outputFormat        output.OutputFormat
+	out                 io.Writer
+}
+
+func NewDescribeTenantUseCase(config *config.Config, name string, outputFormat output.OutputFormat) UseCase {
+	useCase := &describeTenantUseCase{
+		useCaseBase:  NewUseCaseBase("describe-tenant", config),
+		name:         name,
+		outputFormat: outputFormat,
+		out:          os.Stdout,
+	}
+	return useCase
+}
+
+func (u *describeTenantUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
+
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if err != nil {
+		return err
+	}
+
+	u.conn = conn
+	u.tenantClient = api.NewTenantClient(u.conn)
+	u.clusterClient = api.NewClusterClient(u.conn)
+	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
+	u.userClient = api.NewUserClient(u.conn)
+	u.setInitialized()
+
+	return nil
+}
+
+func (u *describeTenantUseCase) collect(ctx context.Context) (*tenantDescription, error) {
+	tenant, err := u.tenantClient.GetByName(ctx, wrapperspb.String(u.name))
+	if err != nil {
+		return nil, fmt.Errorf("failed getting tenant '%s': %w", u.name, err)
+	}
+
+	result := &tenantDescription{
+		Id:       tenant.Id,
+		Name:     tenant.Name,
+		Prefix:   tenant.Prefix,
+		Metadata: newUserNames(u.userClient).metadata(ctx, tenant.Metadata),
+		Members:  []*tenantMemberDescription{},
+		Clusters: []*tenantClusterDescription{},
+	}
+
+	members, err := u.tenantClient.GetUsers(ctx, wrapperspb.String(tenant.Id))
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// Read next
+		member, err := members.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		roles := append([]string{}, member.TenantRoles...)
+		sort.Strings(roles)
+		result.Members = append(result.Members, &tenantMemberDescription{
+			Id:    member.Id,
+			Name:  member.Name,
+			Email: member.Email,
+			Roles: roles,
+		})
+	}
+	sort.Slice(result.Members, func(i, j int) bool {
+		return result.Members[i].Email < result.Members[j].Email
+	})
+
+	mappings, err := u.clusterAccessClient.GetTenantClusterMappingsByTenantId(ctx, wrapperspb.String(tenant.Id))
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// Read next
+		mapping, err := mappings.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		cluster, err := u.clusterClient.GetById(ctx, wrapperspb.String(mapping.ClusterId))
+		if err != nil {
+			return nil, err
+		}
+		clusterDescription := &tenantClusterDescription{
+			Id:               cluster.Id,
+			Name:             cluster.Name,
+			ApiServerAddress: cluster.ApiServerAddress,
+		}
+		if mapping.Metadata != nil {
+			clusterDescription.Since = describeTime(mapping.Metadata.Created)
+		}
+		result.Clusters = append(result.Clusters, clusterDescription)
+	}
+	sort.Slice(result.Clusters, func(i, j int) bool {
+		return result.Clusters[i].Name < result.Clusters[j].Name
+	})
+
+	return result, nil
+}
+
+func (u *describeTenantUseCase) render(result *tenantDescription) error {
+	if u.outputFormat == output.JSONFormat {
+		return output.WriteJSON(u.out, result)
+	}
+
+	fields := []describeField{
+		{"Name", result.Name},
+		{"ID", result.Id},
+		{"Prefix", result.Prefix},
+	}
+	fields = append(fields, metadataFields(result.Metadata)...)
+
+	var members [][]interface{}
+	for _, member := range result.Members {
+		members = append(members, []interface{}{member.Name, member.Email, strings.Join(member.Roles, ", ")})
+	}
+	var clusters [][]interface{}
+	for _, cluster := range result.Clusters {
+		clusters = append(clusters, []interface{}{cluster.Name, cluster.ApiServerAddress, formatDescribeTime(cluster.Since)})
+	}
+
+	return renderDescription(u.out, fields, []describeSection{
+		{"Members", []string{"NAME", "EMAIL", "ROLES"}, members},
+		{"Clusters", []string{"NAME", "API SERVER ADDRESS", "SINCE"}, clusters},
+	})
+}
+
+func (u *describeTenantUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	result, err := u.collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.render(result)
+}
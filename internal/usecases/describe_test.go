@@ -0,0 +1,221 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/output"
+	mdomain "github.com/finleap-connect/monoctl/test/mock/domain"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/roles"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/scopes"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var _ = Describe("Describe", func() {
+	var (
+		mockCtrl *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	var (
+		ctx  = context.Background()
+		conf = config.NewConfig()
+
+		expectedCreator = &projections.User{
+			Id:   uuid.New().String(),
+			Name: "Admin",
+		}
+		expectedUser = &projections.User{
+			Id:    uuid.New().String(),
+			Name:  "Jane Doe",
+			Email: "jane.doe@monoskope.io",
+		}
+		expectedTenant = &projections.Tenant{
+			Id:     uuid.New().String(),
+			Name:   "the-tenant",
+			Prefix: "tt",
+		}
+		expectedCluster = &projections.Cluster{
+			Id:               uuid.New().String(),
+			Name:             "the-cluster",
+			ApiServerAddress: "https://the-cluster.example.com",
+			Metadata: &projections.LifecycleMetadata{
+				Created:     timestamppb.New(time.Now().Add(-time.Hour)),
+				CreatedById: expectedCreator.Id,
+			},
+		}
+		expectedMapping = &projections.TenantClusterBinding{
+			Id:        uuid.New().String(),
+			ClusterId: expectedCluster.Id,
+			TenantId:  expectedTenant.Id,
+			Metadata: &projections.LifecycleMetadata{
+				Created: timestamppb.New(time.Now().Add(-time.Minute)),
+			},
+		}
+	)
+
+	It("should describe a cluster with its tenants", func() {
+		cluster := proto.Clone(expectedCluster).(*projections.Cluster)
+		cluster.CaCertBundle = newTestCABundle("the-cluster-ca", time.Now().Add(24*time.Hour))
+
+		mockClusterClient := mdomain.NewMockClusterClient(mockCtrl)
+		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
+		mockTenantClient := mdomain.NewMockTenantClient(mockCtrl)
+		mockUserClient := mdomain.NewMockUserClient(mockCtrl)
+
+		mockClusterClient.EXPECT().GetByName(ctx, wrapperspb.String(cluster.Name)).Return(cluster, nil)
+		mockUserClient.EXPECT().GetById(ctx, wrapperspb.String(expectedCreator.Id)).Return(expectedCreator, nil)
+		mappingsClient := mdomain.NewMockClusterAccess_GetTenantClusterMappingsByClusterIdClient(mockCtrl)
+		mappingsClient.EXPECT().Recv().Return(expectedMapping, nil)
+		mappingsClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterAccessClient.EXPECT().GetTenantClusterMappingsByClusterId(ctx, wrapperspb.String(cluster.Id)).Return(mappingsClient, nil)
+		mockTenantClient.EXPECT().GetById(ctx, wrapperspb.String(expectedTenant.Id)).Return(expectedTenant, nil)
+
+		out := new(bytes.Buffer)
+		uc := NewDescribeClusterUseCase(conf, cluster.Name, output.JSONFormat).(*describeClusterUseCase)
+		uc.clusterClient = mockClusterClient
+		uc.clusterAccessClient = mockClusterAccessClient
+		uc.tenantClient = mockTenantClient
+		uc.userClient = mockUserClient
+		uc.out = out
+		uc.setInitialized()
+
+		Expect(uc.Run(ctx)).To(Succeed())
+
+		result := new(clusterDescription)
+		Expect(json.Unmarshal(out.Bytes(), result)).To(Succeed())
+		Expect(result.Id).To(Equal(cluster.Id))
+		Expect(result.Metadata.CreatedBy).To(Equal(expectedCreator.Name))
+		Expect(result.CAError).To(BeEmpty())
+		Expect(result.CACertificates).To(HaveLen(1))
+		Expect(result.CACertificates[0].Subject).To(Equal("CN=the-cluster-ca"))
+		Expect(result.Tenants).To(HaveLen(1))
+		Expect(result.Tenants[0].Name).To(Equal(expectedTenant.Name))
+		Expect(result.Tenants[0].Since).ToNot(BeNil())
+	})
+
+	It("should describe a tenant with its members and clusters", func() {
+		mockTenantClient := mdomain.NewMockTenantClient(mockCtrl)
+		mockClusterClient := mdomain.NewMockClusterClient(mockCtrl)
+		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
+
+		mockTenantClient.EXPECT().GetByName(ctx, wrapperspb.String(expectedTenant.Name)).Return(expectedTenant, nil)
+		usersClient := mdomain.NewMockTenant_GetUsersClient(mockCtrl)
+		usersClient.EXPECT().Recv().Return(&projections.TenantUser{
+			Id:          expectedUser.Id,
+			Name:        expectedUser.Name,
+			Email:       expectedUser.Email,
+			TenantId:    expectedTenant.Id,
+			TenantRoles: []string{string(roles.User), string(roles.Admin)},
+		}, nil)
+		usersClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockTenantClient.EXPECT().GetUsers(ctx, wrapperspb.String(expectedTenant.Id)).Return(usersClient, nil)
+		mappingsClient := mdomain.NewMockClusterAccess_GetTenantClusterMappingsByTenantIdClient(mockCtrl)
+		mappingsClient.EXPECT().Recv().Return(expectedMapping, nil)
+		mappingsClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterAccessClient.EXPECT().GetTenantClusterMappingsByTenantId(ctx, wrapperspb.String(expectedTenant.Id)).Return(mappingsClient, nil)
+		mockClusterClient.EXPECT().GetById(ctx, wrapperspb.String(expectedCluster.Id)).Return(expectedCluster, nil)
+
+		out := new(bytes.Buffer)
+		uc := NewDescribeTenantUseCase(conf, expectedTenant.Name, output.JSONFormat).(*describeTenantUseCase)
+		uc.tenantClient = mockTenantClient
+		uc.clusterClient = mockClusterClient
+		uc.clusterAccessClient = mockClusterAccessClient
+		uc.userClient = mdomain.NewMockUserClient(mockCtrl)
+		uc.out = out
+		uc.setInitialized()
+
+		Expect(uc.Run(ctx)).To(Succeed())
+
+		result := new(tenantDescription)
+		Expect(json.Unmarshal(out.Bytes(), result)).To(Succeed())
+		Expect(result.Prefix).To(Equal(expectedTenant.Prefix))
+		Expect(result.Members).To(HaveLen(1))
+		Expect(result.Members[0].Roles).To(Equal([]string{string(roles.Admin), string(roles.User)}))
+		Expect(result.Clusters).To(HaveLen(1))
+		Expect(result.Clusters[0].Name).To(Equal(expectedCluster.Name))
+	})
+
+	It("should describe a user with rolebindings and cluster access", func() {
+		mockUserClient := mdomain.NewMockUserClient(mockCtrl)
+		mockTenantClient := mdomain.NewMockTenantClient(mockCtrl)
+		mockClusterClient := mdomain.NewMockClusterClient(mockCtrl)
+		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
+
+		mockUserClient.EXPECT().GetByEmail(ctx, wrapperspb.String(expectedUser.Email)).Return(expectedUser, nil)
+		roleBindingsClient := mdomain.NewMockUser_GetRoleBindingsByIdClient(mockCtrl)
+		for _, role := range []string{string(roles.User), string(roles.Admin)} {
+			roleBindingsClient.EXPECT().Recv().Return(&projections.UserRoleBinding{
+				Id:       uuid.New().String(),
+				UserId:   expectedUser.Id,
+				Role:     role,
+				Scope:    string(scopes.Tenant),
+				Resource: expectedTenant.Id,
+			}, nil)
+		}
+		roleBindingsClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockUserClient.EXPECT().GetRoleBindingsById(ctx, wrapperspb.String(expectedUser.Id)).Return(roleBindingsClient, nil)
+		mockTenantClient.EXPECT().GetById(ctx, wrapperspb.String(expectedTenant.Id)).Return(expectedTenant, nil)
+		mappingsClient := mdomain.NewMockClusterAccess_GetTenantClusterMappingsByTenantIdClient(mockCtrl)
+		mappingsClient.EXPECT().Recv().Return(expectedMapping, nil)
+		mappingsClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterAccessClient.EXPECT().GetTenantClusterMappingsByTenantId(ctx, wrapperspb.String(expectedTenant.Id)).Return(mappingsClient, nil)
+		mockClusterClient.EXPECT().GetById(ctx, wrapperspb.String(expectedCluster.Id)).Return(expectedCluster, nil)
+
+		out := new(bytes.Buffer)
+		uc := NewDescribeUserUseCase(conf, expectedUser.Email, output.TableFormat).(*describeUserUseCase)
+		uc.userClient = mockUserClient
+		uc.tenantClient = mockTenantClient
+		uc.clusterClient = mockClusterClient
+		uc.clusterAccessClient = mockClusterAccessClient
+		uc.out = out
+		uc.setInitialized()
+
+		result, err := uc.collect(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RoleBindings).To(HaveLen(2))
+		Expect(result.RoleBindings[0].Resource).To(Equal(expectedTenant.Name))
+		Expect(result.Clusters).To(HaveLen(1))
+		Expect(result.Clusters[0].Tenant).To(Equal(expectedTenant.Name))
+		Expect(result.Clusters[0].TenantRoles).To(Equal([]string{string(roles.Admin), string(roles.User)}))
+
+		Expect(uc.render(result)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("Email:"))
+		Expect(out.String()).To(ContainSubstring(expectedUser.Email))
+		Expect(out.String()).To(MatchRegexp(`(?m)^ROLE\s+SCOPE\s+RESOURCE\s*$`))
+		Expect(out.String()).To(MatchRegexp(`(?m)^admin\s+tenant\s+the-tenant\s*$`))
+		Expect(out.String()).To(MatchRegexp(`(?m)^the-cluster\s+the-tenant\s+admin, user\s*$`))
+	})
+})
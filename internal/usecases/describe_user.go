@@ -0,0 +1,255 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/output"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/scopes"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type userDescription struct {
+	Id           string                          `json:"id"`
+	Name         string                          `json:"name"`
+	Email        string                          `json:"email"`
+	Source       string                          `json:"source"`
+	Metadata     *describeMetadata               `json:"metadata"`
+	RoleBindings []*userRoleBindingDescription   `json:"roleBindings"`
+	Clusters     []*userClusterAccessDescription `json:"clusters"`
+}
+
+type userRoleBindingDescription struct {
+	Id       string `json:"id"`
+	Role     string `json:"role"`
+	Scope    string `json:"scope"`
+	Resource string `json:"resource,omitempty"`
+}
+
+// userClusterAccessDescription is a cluster the user has access to by being member of a tenant
+type userClusterAccessDescription struct {
+	Id          string   `json:"id"`
+	Name        string   `json:"name"`
+	Tenant      string   `json:"tenant"`
+	TenantRoles []string `json:"tenantRoles"`
+}
+
+// describeUserUseCase provides the internal use-case of showing a user with their rolebindings and cluster access.
+type describeUserUseCase struct {
+	useCaseBase
+	conn                *ggrpc.ClientConn
+	userClient          api.UserClient
+	tenantClient        api.TenantClient
+	clusterClient       api.ClusterClient
+	clusterAccessClient api.ClusterAccessClient
+	email               string
+	outputFormat        output.OutputFormat
+	out                 io.Writer
+}
+
+func NewDescribeUserUseCase(config *config.Config, email string, outputFormat output.OutputFormat) UseCase {
+	useCase := &describeUserUseCase{
+		useCaseBase:  NewUseCaseBase("describe-user", config),
+		email:        email,
+		outputFormat: outputFormat,
+		out:          os.Stdout,
+	}
+	return useCase
+}
+
+func (u *describeUserUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
+
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if err != nil {
+		return err
+	}
+
+	u.conn = conn
+	u.userClient = api.NewUserClient(u.conn)
+	u.tenantClient = api.NewTenantClient(u.conn)
+	u.clusterClient = api.NewClusterClient(u.conn)
+	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
+	u.setInitialized()
+
+	return nil
+}
+
+// collectClusters returns the clusters the tenant has access to
+func (u *describeUserUseCase) collectClusters(ctx context.Context, tenant *projections.Tenant, roles []string) ([]*userClusterAccessDescription, error) {
+	mappings, err := u.clusterAccessClient.GetTenantClusterMappingsByTenantId(ctx, wrapperspb.String(tenant.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	var clusters []*userClusterAccessDescription
+	for {
+		// Read next
+		mapping, err := mappings.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		cluster, err := u.clusterClient.GetById(ctx, wrapperspb.String(mapping.ClusterId))
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, &userClusterAccessDescription{
+			Id:          cluster.Id,
+			Name:        cluster.Name,
+			Tenant:      tenant.Name,
+			TenantRoles: roles,
+		})
+	}
+	return clusters, nil
+}
+
+func (u *describeUserUseCase) collect(ctx context.Context) (*userDescription, error) {
+	user, err := u.userClient.GetByEmail(ctx, wrapperspb.String(u.email))
+	if err != nil {
+		return nil, fmt.Errorf("failed getting user '%s': %w", u.email, err)
+	}
+
+	result := &userDescription{
+		Id:           user.Id,
+		Name:         user.Name,
+		Email:        user.Email,
+		Source:       user.Source.String(),
+		Metadata:     newUserNames(u.userClient).metadata(ctx, user.Metadata),
+		RoleBindings: []*userRoleBindingDescription{},
+		Clusters:     []*userClusterAccessDescription{},
+	}
+
+	roleBindingsStream, err := u.userClient.GetRoleBindingsById(ctx, wrapperspb.String(user.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	tenants := make(map[string]*projections.Tenant)
+	tenantRoles := make(map[string][]string)
+	for {
+		// Read next
+		rb, err := roleBindingsStream.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		roleBinding := &userRoleBindingDescription{
+			Id:       rb.Id,
+			Role:     rb.Role,
+			Scope:    rb.Scope,
+			Resource: rb.Resource,
+		}
+		if rb.Scope == string(scopes.Tenant) {
+			tenant, ok := tenants[rb.Resource]
+			if !ok {
+				tenant, err = u.tenantClient.GetById(ctx, wrapperspb.String(rb.Resource))
+				if err != nil {
+					return nil, err
+				}
+				tenants[tenant.Id] = tenant
+			}
+			roleBinding.Resource = tenant.Name
+			tenantRoles[tenant.Id] = append(tenantRoles[tenant.Id], rb.Role)
+		}
+		result.RoleBindings = append(result.RoleBindings, roleBinding)
+	}
+
+	for tenantId, tenant := range tenants {
+		roles := tenantRoles[tenantId]
+		sort.Strings(roles)
+		clusters, err := u.collectClusters(ctx, tenant, roles)
+		if err != nil {
+			return nil, err
+		}
+		result.Clusters = append(result.Clusters, clusters...)
+	}
+	sort.Slice(result.Clusters, func(i, j int) bool {
+		if result.Clusters[i].Name != result.Clusters[j].Name {
+			return result.Clusters[i].Name < result.Clusters[j].Name
+		}
+		return result.Clusters[i].Tenant < result.Clusters[j].Tenant
+	})
+
+	return result, nil
+}
+
+func (u *describeUserUseCase) render(result *userDescription) error {
+	if u.outputFormat == output.JSONFormat {
+		return output.WriteJSON(u.out, result)
+	}
+
+	fields := []describeField{
+		{"Name", result.Name},
+		{"Email", result.Email},
+		{"ID", result.Id},
+		{"Source", result.Source},
+	}
+	fields = append(fields, metadataFields(result.Metadata)...)
+
+	var roleBindings [][]interface{}
+	for _, rb := range result.RoleBindings {
+		roleBindings = append(roleBindings, []interface{}{rb.Role, rb.Scope, rb.Resource})
+	}
+	var clusters [][]interface{}
+	for _, cluster := range result.Clusters {
+		clusters = append(clusters, []interface{}{cluster.Name, cluster.Tenant, strings.Join(cluster.TenantRoles, ", ")})
+	}
+
+	return renderDescription(u.out, fields, []describeSection{
+		{"Rolebindings", []string{"ROLE", "SCOPE", "RESOURCE"}, roleBindings},
+		{"Cluster Access", []string{"CLUSTER", "VIA TENANT", "TENANT ROLES"}, clusters},
+	})
+}
+
+func (u *describeUserUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	result, err := u.collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.render(result)
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/output"
+)
+
+// getAPITokenRecordsUseCase provides the internal use-case of listing the local records of API tokens issued via monoctl.
+// The m8 control plane does not keep track of issued tokens, they are recorded in the monoconfig on creation.
+type getAPITokenRecordsUseCase struct {
+	useCaseBase
+	tableFactory  *output.TableFactory
+	outputOptions *output.OutputOptions
+}
+
+func NewGetAPITokenRecordsUseCase(config *config.Config, outputOptions *output.OutputOptions) UseCase {
+	useCase := &getAPITokenRecordsUseCase{
+		useCaseBase:   NewUseCaseBase("get-api-token-records", config),
+		outputOptions: outputOptions,
+	}
+
+	useCase.tableFactory = output.NewTableFactory().
+		SetHeader([]string{"ID", "USER", "SCOPES", "VALIDITY", "AGE", "EXPIRY", "STATUS"}).
+		SetColumnFormatter("VALIDITY", output.DefaultAgeColumnFormatter()).
+		SetColumnFormatter("AGE", output.DefaultAgeColumnFormatter()).
+		SetSortColumn(outputOptions.SortOptions.SortByColumn).
+		SetSortOrder(outputOptions.SortOptions.Order).
+		SetExportFormat(outputOptions.ExportOptions.Format).
+		SetExportFile(outputOptions.ExportOptions.File)
+
+	return useCase
+}
+
+func (u *getAPITokenRecordsUseCase) Run(ctx context.Context) error {
+	var data [][]interface{}
+	for _, apiToken := range u.config.APITokens {
+		// Expired tokens are treated like deleted resources
+		if !u.outputOptions.ShowDeleted && apiToken.IsExpired() {
+			continue
+		}
+		data = append(data, []interface{}{
+			apiToken.Id,
+			apiToken.User,
+			strings.Join(apiToken.Scopes, ","),
+			apiToken.Validity,
+			time.Since(apiToken.Created),
+			apiToken.Expiry.Local().Format(time.RFC3339),
+			apiToken.Status(),
+		})
+	}
+
+	u.tableFactory.SetData(data) // Add Bulk Data
+	tbl, err := u.tableFactory.ToTable()
+	if err != nil {
+		return err
+	}
+
+	tbl.Render()
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/output"
+)
+
+// getClusterAuthUseCase provides the internal use-case of listing the cached cluster tokens
+type getClusterAuthUseCase struct {
+	useCaseBase
+}
+
+// NewGetClusterAuthUseCase returns the use-case listing the cached cluster tokens, no connection to the m8 control plane is needed
+func NewGetClusterAuthUseCase(config *config.Config) UseCase {
+	useCase := &getClusterAuthUseCase{
+		useCaseBase: NewUseCaseBase("get-cluster-auth", config),
+	}
+	return useCase
+}
+
+func (u *getClusterAuthUseCase) Run(ctx context.Context) error {
+	var data [][]interface{}
+	for key, authInfo := range u.config.ClusterAuthInformation {
+		clusterId, username, role, ok := config.ParseClusterAuthKey(key)
+		if !ok {
+			continue
+		}
+		status := "valid"
+		if !authInfo.IsValidExact() {
+			status = "expired"
+		}
+		data = append(data, []interface{}{
+			clusterId,
+			username,
+			role,
+			authInfo.Expiry.Local().Format(time.RFC3339),
+			status,
+		})
+	}
+
+	tbl, err := output.NewTableFactory().
+		SetHeader([]string{"CLUSTER ID", "USER", "ROLE", "EXPIRY", "STATUS"}).
+		SetData(data).
+		ToTable()
+	if err != nil {
+		return err
+	}
+	tbl.Render()
+	return nil
+}
@@ -17,14 +17,23 @@ package usecases
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/finleap-connect/monoctl/internal/config"
 	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/k8s"
 	api "github.com/finleap-connect/monoskope/pkg/api/domain"
 	apiGateway "github.com/finleap-connect/monoskope/pkg/api/gateway"
+	"github.com/google/uuid"
 	ggrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kclientauthv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
 	kclientauth "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
 )
 
@@ -33,33 +42,66 @@ type getClusterCredentialsUseCase struct {
 	conn                 *ggrpc.ClientConn
 	configManager        *config.ClientConfigManager
 	clusterServiceClient api.ClusterClient
+	clusterAccessClient  api.ClusterAccessClient
 	clusterAuthClient    apiGateway.ClusterAuthClient
-	clusterId            string
-	clusterRole          string
+	// clusterId is the id or name of the cluster given by argument
+	clusterId   string
+	clusterRole string
+	// getExecInfo returns the ExecCredential passed by kubectl, nil if not called by kubectl
+	getExecInfo func() (*k8s.ExecInfo, error)
+	execInfo    *k8s.ExecInfo
+	// cachedOnly serves only cached credentials without dialing the gateway
+	cachedOnly bool
+	// dial connects to the gateway, it is only called if the credentials are not cached
+	dial func(ctx context.Context, conf *config.Config) (*ggrpc.ClientConn, error)
+	out  io.Writer
 }
 
+// ErrClusterCredentialsNotCached is returned if the credentials can not be served without the gateway
+var ErrClusterCredentialsNotCached = errors.New("cluster credentials not cached")
+
+// NewGetClusterCredentialsUseCase returns the use-case writing the ExecCredential for the role on the cluster to stdout.
+// The cluster is given by id or name. If called by kubectl the cluster passed by kubectl is used, the cluster given is
+// only a fallback and can be empty then.
 func NewGetClusterCredentialsUseCase(configManager *config.ClientConfigManager, clusterId, role string) UseCase {
 	useCase := &getClusterCredentialsUseCase{
 		useCaseBase:   NewUseCaseBase("get-cluster-credentials", configManager.GetConfig()),
 		configManager: configManager,
 		clusterId:     clusterId,
 		clusterRole:   role,
+		getExecInfo:   k8s.GetExecInfo,
+		dial:          mgrpc.CreateGrpcConnectionAuthenticatedFromConfig,
+		out:           os.Stdout,
 	}
 	return useCase
 }
 
+// NewGetCachedClusterCredentialsUseCase returns the use-case writing the ExecCredential for the role on the cluster to stdout
+// if valid credentials are cached. Neither the gateway is dialed nor the auth flow is locked, if the credentials are not cached
+// ErrClusterCredentialsNotCached is returned.
+func NewGetCachedClusterCredentialsUseCase(configManager *config.ClientConfigManager, clusterId, role string) UseCase {
+	useCase := NewGetClusterCredentialsUseCase(configManager, clusterId, role).(*getClusterCredentialsUseCase)
+	useCase.cachedOnly = true
+	return useCase
+}
+
 func (u *getClusterCredentialsUseCase) init(ctx context.Context) error {
 	if u.initialized {
 		return nil
 	}
 
-	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if u.cachedOnly {
+		return ErrClusterCredentialsNotCached
+	}
+
+	conn, err := u.dial(ctx, u.config)
 	if err != nil {
 		return err
 	}
 
 	u.conn = conn
 	u.clusterServiceClient = api.NewClusterClient(u.conn)
+	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
 	u.clusterAuthClient = apiGateway.NewClusterAuthClient(u.conn)
 
 	u.setInitialized()
@@ -68,29 +110,59 @@ func (u *getClusterCredentialsUseCase) init(ctx context.Context) error {
 }
 
 func (u *getClusterCredentialsUseCase) run(ctx context.Context) error {
-	clusterAuthInfo := u.config.GetClusterAuthInformation(u.clusterId, u.config.AuthInformation.Username, u.clusterRole)
-	if clusterAuthInfo == nil || !clusterAuthInfo.IsValidExact() {
-		// Get cluster credentials
-		_, err := u.requestClusterAuthInformation(ctx, u.clusterId)
-		if err != nil {
-			return err
-		}
-		clusterAuthInfo = u.config.GetClusterAuthInformation(u.clusterId, u.config.AuthInformation.Username, u.clusterRole)
+	var err error
+	if u.execInfo, err = u.getExecInfo(); err != nil {
+		return err
+	}
 
+	apiVersion := u.config.GetExecCredentialAPIVersion()
+	if u.execInfo != nil {
+		// Answer in the version kubectl asked for
+		apiVersion = u.execInfo.APIVersion
+	}
+	if err := k8s.ValidateExecCredentialAPIVersion(apiVersion); err != nil {
+		return err
+	}
+
+	clusterId, err := u.getClusterId(ctx)
+	if err != nil {
+		return err
+	}
+
+	clusterAuthInfo, err := u.getClusterAuthInformation(ctx, clusterId)
+	if err != nil {
+		return err
 	}
 
 	// Convert to kubectl readable format
-	execCredential := kclientauth.ExecCredential{
-		TypeMeta: v1.TypeMeta{
-			Kind:       "ExecCredential",
-			APIVersion: "client.authentication.k8s.io/v1beta1",
-		},
-		Status: &kclientauth.ExecCredentialStatus{
-			Token: clusterAuthInfo.Token,
-			ExpirationTimestamp: &v1.Time{
-				Time: clusterAuthInfo.Expiry,
+	var execCredential interface{}
+	switch apiVersion {
+	case k8s.ExecCredentialV1:
+		execCredential = kclientauthv1.ExecCredential{
+			TypeMeta: v1.TypeMeta{
+				Kind:       "ExecCredential",
+				APIVersion: apiVersion,
 			},
-		},
+			Status: &kclientauthv1.ExecCredentialStatus{
+				Token: clusterAuthInfo.Token,
+				ExpirationTimestamp: &v1.Time{
+					Time: clusterAuthInfo.Expiry,
+				},
+			},
+		}
+	default:
+		execCredential = kclientauth.ExecCredential{
+			TypeMeta: v1.TypeMeta{
+				Kind:       "ExecCredential",
+				APIVersion: apiVersion,
+			},
+			Status: &kclientauth.ExecCredentialStatus{
+				Token: clusterAuthInfo.Token,
+				ExpirationTimestamp: &v1.Time{
+					Time: clusterAuthInfo.Expiry,
+				},
+			},
+		}
 	}
 
 	// Marshal
@@ -99,12 +171,134 @@ func (u *getClusterCredentialsUseCase) run(ctx context.Context) error {
 		return err
 	}
 
-	// Write marshalled yaml to stdout
-	fmt.Println(string(bytes))
+	// Write marshalled json to stdout, nothing else must be written there
+	fmt.Fprintln(u.out, string(bytes))
 
 	return nil
 }
 
+// getClusterId returns the id of the cluster kubectl is connecting to, falling back to the one given by argument
+func (u *getClusterCredentialsUseCase) getClusterId(ctx context.Context) (string, error) {
+	if u.execInfo == nil {
+		if u.clusterId == "" {
+			return "", errors.New("cluster must be specified")
+		}
+		return u.resolveClusterId(ctx)
+	}
+
+	if clusterId := u.execInfo.ClusterConfig().ClusterId; clusterId != "" {
+		if u.clusterId != "" && u.clusterId != clusterId {
+			u.log.Info("Cluster passed by kubectl differs from argument, using the one of kubectl.", "cluster", clusterId, "argument", u.clusterId)
+		}
+		return clusterId, nil
+	}
+	if u.clusterId != "" {
+		return u.resolveClusterId(ctx)
+	}
+
+	// Find the cluster by the address of its API server
+	server := u.execInfo.Server()
+	if server == "" {
+		return "", errors.New("cluster must be specified, kubectl passed no cluster information")
+	}
+	if err := u.init(ctx); err != nil {
+		return "", err
+	}
+	stream, err := u.clusterServiceClient.GetAll(ctx, &api.GetAllRequest{})
+	if err != nil {
+		return "", err
+	}
+	for {
+		cluster, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if cluster.ApiServerAddress == server {
+			return cluster.Id, nil
+		}
+	}
+	return "", fmt.Errorf("no cluster with API server '%s' found", server)
+}
+
+// getClusterAuthInformation returns the cached credentials for the role on the cluster, requesting new ones if needed
+func (u *getClusterCredentialsUseCase) getClusterAuthInformation(ctx context.Context, clusterId string) (*config.AuthInformation, error) {
+	if u.config.AuthInformation == nil {
+		if u.cachedOnly {
+			return nil, ErrClusterCredentialsNotCached
+		}
+		return nil, errors.New("not authenticated")
+	}
+
+	clusterAuthInfo := u.config.GetClusterAuthInformation(clusterId, u.config.AuthInformation.Username, u.clusterRole)
+	if clusterAuthInfo != nil && clusterAuthInfo.IsValidExact() {
+		return clusterAuthInfo, nil
+	}
+
+	// The gateway is only dialed if the credentials are not cached
+	if err := u.init(ctx); err != nil {
+		return nil, err
+	}
+
+	// Check the role before asking the gateway for a token
+	if err := u.validateRole(ctx, clusterId); err != nil {
+		return nil, err
+	}
+
+	// Get cluster credentials
+	if _, err := u.requestClusterAuthInformation(ctx, clusterId); err != nil {
+		return nil, err
+	}
+	return u.config.GetClusterAuthInformation(clusterId, u.config.AuthInformation.Username, u.clusterRole), nil
+}
+
+// resolveClusterId returns the id of the cluster given by argument, looking it up by name if it is no id
+func (u *getClusterCredentialsUseCase) resolveClusterId(ctx context.Context) (string, error) {
+	if _, err := uuid.Parse(u.clusterId); err == nil {
+		return u.clusterId, nil
+	}
+
+	// Cached credentials are stored by id, names can only be resolved by the gateway
+	if err := u.init(ctx); err != nil {
+		return "", err
+	}
+	cluster, err := u.clusterServiceClient.GetByName(ctx, wrapperspb.String(u.clusterId))
+	if err != nil {
+		return "", fmt.Errorf("failed getting cluster '%s': %w", u.clusterId, err)
+	}
+	return cluster.Id, nil
+}
+
+// validateRole checks that the user has the requested role on the cluster
+func (u *getClusterCredentialsUseCase) validateRole(ctx context.Context, clusterId string) error {
+	clusterAccesses, err := getClusterAccesses(ctx, u.clusterAccessClient)
+	if err != nil {
+		return err
+	}
+	for _, clusterAccess := range clusterAccesses {
+		if clusterAccess.Cluster.Id != clusterId {
+			continue
+		}
+		var roles []string
+		for _, clusterRole := range clusterAccess.ClusterRoles {
+			if clusterRole.Role == u.clusterRole {
+				return nil
+			}
+			roles = append(roles, clusterRole.Role)
+		}
+		sort.Strings(roles)
+		return fmt.Errorf("role '%s' not available, you have roles [%s] on cluster '%s'", u.clusterRole, strings.Join(roles, ","), clusterAccess.Cluster.Name)
+	}
+
+	cluster := clusterId
+	if u.clusterId != "" {
+		cluster = u.clusterId
+	}
+	return fmt.Errorf("you have no access to cluster '%s'", cluster)
+}
+
 func (u *getClusterCredentialsUseCase) requestClusterAuthInformation(ctx context.Context, clusterId string) (response *apiGateway.ClusterAuthTokenResponse, err error) {
 	// Get token from gateway
 	response, err = u.clusterAuthClient.GetAuthToken(ctx, &apiGateway.ClusterAuthTokenRequest{
@@ -128,15 +322,14 @@ func (u *getClusterCredentialsUseCase) requestClusterAuthInformation(ctx context
 }
 
 func (u *getClusterCredentialsUseCase) Run(ctx context.Context) error {
-	err := u.init(ctx)
-	if err != nil {
-		return err
-	}
-	if u.conn != nil {
-		defer u.conn.Close()
-	}
+	// The gateway is dialed lazily by run
+	defer func() {
+		if u.conn != nil {
+			u.conn.Close()
+		}
+	}()
 
-	err = u.run(ctx)
+	err := u.run(ctx)
 	if err != nil {
 		return err
 	}
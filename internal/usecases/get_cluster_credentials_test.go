@@ -15,23 +15,39 @@
 package usecases
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
 	"time"
 
 	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/k8s"
 	mdomain "github.com/finleap-connect/monoctl/test/mock/domain"
 	mgw "github.com/finleap-connect/monoctl/test/mock/gateway"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
 	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
 	gw "github.com/finleap-connect/monoskope/pkg/api/gateway"
 	mk8s "github.com/finleap-connect/monoskope/pkg/k8s"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/uuid"
 	testutil_fs "github.com/kubism/testutil/pkg/fs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/zalando/go-keyring"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	kclientauthv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
 )
 
 var _ = Describe("GetClusterCredentials", func() {
@@ -73,6 +89,19 @@ var _ = Describe("GetClusterCredentials", func() {
 		}
 	}
 
+	// expectClusterAccess expects the cluster access of the user to be queried, having the role on the clusters
+	expectClusterAccess := func(mockClusterAccessClient *mdomain.MockClusterAccessClient, clusters ...*projections.Cluster) {
+		getClusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+		for _, cluster := range clusters {
+			getClusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+				Cluster:      cluster,
+				ClusterRoles: []*projections.ClusterRole{{Scope: projections.ClusterRole_CLUSTER, Role: expectedRole}},
+			}, nil)
+		}
+		getClusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(getClusterAccessClient, nil)
+	}
+
 	It("should run", func() {
 		var err error
 
@@ -91,9 +120,12 @@ var _ = Describe("GetClusterCredentials", func() {
 		}
 
 		mockClusterClient := mdomain.NewMockClusterClient(mockCtrl)
+		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
 		mockClusterAuthClient := mgw.NewMockClusterAuthClient(mockCtrl)
 
 		expectedClusters := getClusters()
+		expectClusterAccess(mockClusterAccessClient, expectedClusters...)
+		expectClusterAccess(mockClusterAccessClient, expectedClusters...)
 
 		// mockClusterClient.EXPECT().GetByName(ctx, wrapperspb.String(expectedClusters[0].Name)).Return(expectedClusters[0], nil)
 
@@ -116,6 +148,7 @@ var _ = Describe("GetClusterCredentials", func() {
 
 		uc := NewGetClusterCredentialsUseCase(confManager, expectedClusters[0].Id, expectedRole).(*getClusterCredentialsUseCase)
 		uc.clusterServiceClient = mockClusterClient
+		uc.clusterAccessClient = mockClusterAccessClient
 		uc.clusterAuthClient = mockClusterAuthClient
 		uc.setInitialized()
 		err = uc.Run(ctx)
@@ -123,6 +156,7 @@ var _ = Describe("GetClusterCredentials", func() {
 
 		uc = NewGetClusterCredentialsUseCase(confManager, expectedClusters[1].Id, expectedRole).(*getClusterCredentialsUseCase)
 		uc.clusterServiceClient = mockClusterClient
+		uc.clusterAccessClient = mockClusterAccessClient
 		uc.clusterAuthClient = mockClusterAuthClient
 		uc.setInitialized()
 		err = uc.Run(ctx)
@@ -136,6 +170,253 @@ var _ = Describe("GetClusterCredentials", func() {
 		}).Should(Succeed())
 	})
 
+	Context("called by kubectl", func() {
+		var (
+			confManager             *config.ClientConfigManager
+			mockClusterClient       *mdomain.MockClusterClient
+			mockClusterAccessClient *mdomain.MockClusterAccessClient
+			mockClusterAuthClient   *mgw.MockClusterAuthClient
+			tempFile                *testutil_fs.TempFile
+		)
+
+		BeforeEach(func() {
+			keyring.MockInit()
+
+			var err error
+			tempFile, err = testutil_fs.NewTempFile([]byte(fakeConfigData))
+			Expect(err).NotTo(HaveOccurred())
+
+			confManager = config.NewLoaderFromExplicitFile(tempFile.Path)
+			Expect(confManager.LoadConfig()).NotTo(HaveOccurred())
+			confManager.GetConfig().AuthInformation = &config.AuthInformation{
+				Username: "test-user",
+				Expiry:   expectedExpiry,
+			}
+
+			mockClusterClient = mdomain.NewMockClusterClient(mockCtrl)
+			mockClusterAccessClient = mdomain.NewMockClusterAccessClient(mockCtrl)
+			mockClusterAuthClient = mgw.NewMockClusterAuthClient(mockCtrl)
+		})
+
+		AfterEach(func() {
+			tempFile.Close()
+		})
+
+		newUseCase := func(clusterId, execInfo string) (*getClusterCredentialsUseCase, *bytes.Buffer) {
+			uc := NewGetClusterCredentialsUseCase(confManager, clusterId, expectedRole).(*getClusterCredentialsUseCase)
+			uc.clusterServiceClient = mockClusterClient
+			uc.clusterAccessClient = mockClusterAccessClient
+			uc.clusterAuthClient = mockClusterAuthClient
+			uc.getExecInfo = func() (*k8s.ExecInfo, error) {
+				if execInfo == "" {
+					return nil, nil
+				}
+				return k8s.ParseExecInfo(execInfo)
+			}
+			uc.setInitialized()
+			buf := new(bytes.Buffer)
+			uc.out = buf
+			return uc, buf
+		}
+
+		expectAuthToken := func(clusterId string) {
+			expectClusterAccess(mockClusterAccessClient, &projections.Cluster{Id: clusterId, Name: "the-cluster"})
+			mockClusterAuthClient.EXPECT().GetAuthToken(ctx, &gw.ClusterAuthTokenRequest{
+				ClusterId: clusterId,
+				Role:      expectedRole,
+			}).Return(&gw.ClusterAuthTokenResponse{
+				AccessToken: expectedClusterToken,
+				Expiry:      timestamppb.New(expectedExpiry),
+			}, nil)
+		}
+
+		It("answers in the version requested by kubectl for the cluster passed by kubectl", func() {
+			cluster := getClusters()[0]
+			expectAuthToken(cluster.Id)
+
+			uc, buf := newUseCase("", `{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","spec":{"interactive":false,"cluster":{"server":"`+cluster.ApiServerAddress+`","config":{"clusterId":"`+cluster.Id+`"}}}}`)
+			Expect(uc.Run(ctx)).To(Succeed())
+
+			execCredential := &kclientauthv1.ExecCredential{}
+			Expect(json.Unmarshal(buf.Bytes(), execCredential)).To(Succeed())
+			Expect(execCredential.APIVersion).To(Equal(k8s.ExecCredentialV1))
+			Expect(execCredential.Status.Token).To(Equal(expectedClusterToken))
+		})
+
+		It("finds the cluster by the server passed by kubectl", func() {
+			clusters := getClusters()
+			getAllClient := mdomain.NewMockCluster_GetAllClient(mockCtrl)
+			getAllClient.EXPECT().Recv().Return(clusters[0], nil)
+			getAllClient.EXPECT().Recv().Return(clusters[1], nil)
+			mockClusterClient.EXPECT().GetAll(ctx, gomock.Any()).Return(getAllClient, nil)
+			expectAuthToken(clusters[1].Id)
+
+			uc, buf := newUseCase("", `{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","spec":{"cluster":{"server":"`+clusters[1].ApiServerAddress+`"}}}`)
+			Expect(uc.Run(ctx)).To(Succeed())
+			Expect(buf.String()).To(HavePrefix(`{"kind":"ExecCredential","apiVersion":"client.authentication.k8s.io/v1beta1"`))
+			Expect(strings.Count(buf.String(), "\n")).To(Equal(1))
+		})
+
+		It("uses the configured version without kubectl", func() {
+			cluster := getClusters()[0]
+			expectAuthToken(cluster.Id)
+			confManager.GetConfig().ExecCredentialAPIVersion = k8s.ExecCredentialV1
+
+			uc, buf := newUseCase(cluster.Id, "")
+			Expect(uc.Run(ctx)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring(`"apiVersion":"client.authentication.k8s.io/v1"`))
+
+			_, err := k8s.ParseExecInfo(`{"apiVersion":"client.authentication.k8s.io/v1alpha1"}`)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("cluster given by argument", func() {
+		var (
+			confManager             *config.ClientConfigManager
+			mockClusterClient       *mdomain.MockClusterClient
+			mockClusterAccessClient *mdomain.MockClusterAccessClient
+			mockClusterAuthClient   *mgw.MockClusterAuthClient
+			tempFile                *testutil_fs.TempFile
+		)
+
+		BeforeEach(func() {
+			keyring.MockInit()
+
+			var err error
+			tempFile, err = testutil_fs.NewTempFile([]byte(fakeConfigData))
+			Expect(err).NotTo(HaveOccurred())
+
+			confManager = config.NewLoaderFromExplicitFile(tempFile.Path)
+			Expect(confManager.LoadConfig()).NotTo(HaveOccurred())
+			confManager.GetConfig().AuthInformation = &config.AuthInformation{
+				Username: "test-user",
+				Expiry:   expectedExpiry,
+			}
+
+			mockClusterClient = mdomain.NewMockClusterClient(mockCtrl)
+			mockClusterAccessClient = mdomain.NewMockClusterAccessClient(mockCtrl)
+			mockClusterAuthClient = mgw.NewMockClusterAuthClient(mockCtrl)
+		})
+
+		AfterEach(func() {
+			tempFile.Close()
+		})
+
+		newUseCase := func(cluster, role string) *getClusterCredentialsUseCase {
+			uc := NewGetClusterCredentialsUseCase(confManager, cluster, role).(*getClusterCredentialsUseCase)
+			uc.clusterServiceClient = mockClusterClient
+			uc.clusterAccessClient = mockClusterAccessClient
+			uc.clusterAuthClient = mockClusterAuthClient
+			uc.getExecInfo = func() (*k8s.ExecInfo, error) { return nil, nil }
+			uc.setInitialized()
+			uc.out = new(bytes.Buffer)
+			return uc
+		}
+
+		It("resolves the cluster by name", func() {
+			cluster := getClusters()[0]
+			mockClusterClient.EXPECT().GetByName(ctx, wrapperspb.String(cluster.Name)).Return(cluster, nil)
+			expectClusterAccess(mockClusterAccessClient, cluster)
+			mockClusterAuthClient.EXPECT().GetAuthToken(ctx, &gw.ClusterAuthTokenRequest{
+				ClusterId: cluster.Id,
+				Role:      expectedRole,
+			}).Return(&gw.ClusterAuthTokenResponse{
+				AccessToken: expectedClusterToken,
+				Expiry:      timestamppb.New(expectedExpiry),
+			}, nil)
+
+			Expect(newUseCase(cluster.Name, expectedRole).Run(ctx)).To(Succeed())
+			Expect(confManager.GetConfig().GetClusterAuthInformation(cluster.Id, "test-user", expectedRole)).ToNot(BeNil())
+		})
+
+		It("rejects roles the user does not have without asking the gateway", func() {
+			cluster := getClusters()[0]
+			getClusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+			getClusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+				Cluster: cluster,
+				ClusterRoles: []*projections.ClusterRole{
+					{Scope: projections.ClusterRole_CLUSTER, Role: "b"},
+					{Scope: projections.ClusterRole_CLUSTER, Role: "a"},
+				},
+			}, nil)
+			getClusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+			mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(getClusterAccessClient, nil)
+
+			err := newUseCase(cluster.Id, "admin").Run(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("you have roles [a,b] on cluster 'first-cluster'"))
+		})
+
+		It("rejects clusters the user has no access to", func() {
+			cluster := getClusters()[0]
+			expectClusterAccess(mockClusterAccessClient)
+
+			Expect(newUseCase(cluster.Id, expectedRole).Run(ctx)).To(MatchError(fmt.Sprintf("you have no access to cluster '%s'", cluster.Id)))
+		})
+	})
+
+	Context("cached credentials", func() {
+		var (
+			confManager *config.ClientConfigManager
+			tempFile    *testutil_fs.TempFile
+			clusterId   = uuid.New().String()
+		)
+
+		BeforeEach(func() {
+			keyring.MockInit()
+
+			var err error
+			tempFile, err = testutil_fs.NewTempFile([]byte(fakeConfigData))
+			Expect(err).NotTo(HaveOccurred())
+
+			confManager = config.NewLoaderFromExplicitFile(tempFile.Path)
+			Expect(confManager.LoadConfig()).NotTo(HaveOccurred())
+			confManager.GetConfig().AuthInformation = &config.AuthInformation{
+				Username: "test-user",
+				Token:    "this-is-a-token",
+				Expiry:   expectedExpiry,
+			}
+		})
+
+		AfterEach(func() {
+			tempFile.Close()
+		})
+
+		newUseCase := func(uc UseCase) (*getClusterCredentialsUseCase, *bytes.Buffer) {
+			credentialsUseCase := uc.(*getClusterCredentialsUseCase)
+			credentialsUseCase.getExecInfo = func() (*k8s.ExecInfo, error) { return nil, nil }
+			credentialsUseCase.dial = func(ctx context.Context, conf *config.Config) (*ggrpc.ClientConn, error) {
+				Fail("gateway must not be dialed")
+				return nil, nil
+			}
+			buf := new(bytes.Buffer)
+			credentialsUseCase.out = buf
+			return credentialsUseCase, buf
+		}
+
+		It("serves cached credentials without dialing the gateway", func() {
+			conf := confManager.GetConfig()
+			conf.SetClusterAuthInformation(clusterId, "test-user", expectedRole, expectedClusterToken, expectedExpiry)
+			Expect(confManager.SaveConfig()).To(Succeed())
+			Expect(confManager.LoadConfig()).To(Succeed())
+
+			uc, buf := newUseCase(NewGetCachedClusterCredentialsUseCase(confManager, clusterId, expectedRole))
+			Expect(uc.Run(ctx)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring(expectedClusterToken))
+
+			uc, buf = newUseCase(NewGetClusterCredentialsUseCase(confManager, clusterId, expectedRole))
+			Expect(uc.Run(ctx)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring(expectedClusterToken))
+		})
+
+		It("reports missing credentials in cache only mode", func() {
+			uc, buf := newUseCase(NewGetCachedClusterCredentialsUseCase(confManager, clusterId, expectedRole))
+			Expect(uc.Run(ctx)).To(MatchError(ErrClusterCredentialsNotCached))
+			Expect(buf.String()).To(BeEmpty())
+		})
+	})
+
 	// It("should get all clusters credentials for default role only", func() {
 	// 	var err error
 
@@ -187,3 +468,100 @@ var _ = Describe("GetClusterCredentials", func() {
 	// 	}
 	// })
 })
+
+// clusterAuthServer is a gateway serving cluster auth tokens for benchmarks
+type clusterAuthServer struct {
+	gw.UnimplementedClusterAuthServer
+}
+
+func (s *clusterAuthServer) GetAuthToken(ctx context.Context, request *gw.ClusterAuthTokenRequest) (*gw.ClusterAuthTokenResponse, error) {
+	return &gw.ClusterAuthTokenResponse{
+		AccessToken: "some-auth-token",
+		Expiry:      timestamppb.New(time.Now().Add(time.Hour)),
+	}, nil
+}
+
+// clusterAccessServer is a domain query handler serving the access to a single cluster for benchmarks
+type clusterAccessServer struct {
+	api.UnimplementedClusterAccessServer
+	clusterId string
+	role      string
+}
+
+func (s *clusterAccessServer) GetClusterAccessV2(_ *empty.Empty, stream api.ClusterAccess_GetClusterAccessV2Server) error {
+	return stream.Send(&projections.ClusterAccessV2{
+		Cluster:      &projections.Cluster{Id: s.clusterId, Name: "benchmark-cluster"},
+		ClusterRoles: []*projections.ClusterRole{{Scope: projections.ClusterRole_CLUSTER, Role: s.role}},
+	})
+}
+
+// benchmarkClusterCredentials measures getting cluster credentials like kubectl does, including loading the config
+func benchmarkClusterCredentials(b *testing.B, cached bool) {
+	keyring.MockInit()
+	clusterId := uuid.New().String()
+	role := string(mk8s.DefaultRole)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatal(err)
+	}
+	server := ggrpc.NewServer()
+	gw.RegisterClusterAuthServer(server, &clusterAuthServer{})
+	api.RegisterClusterAccessServer(server, &clusterAccessServer{clusterId: clusterId, role: role})
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	dial := func(ctx context.Context, conf *config.Config) (*ggrpc.ClientConn, error) {
+		return ggrpc.DialContext(ctx, lis.Addr().String(), ggrpc.WithTransportCredentials(insecure.NewCredentials()), ggrpc.WithBlock())
+	}
+
+	dir := b.TempDir()
+	configFile := filepath.Join(dir, "config")
+	conf := config.NewConfig()
+	conf.Server = lis.Addr().String()
+	conf.AuthInformation = &config.AuthInformation{Username: "test-user", Token: "this-is-a-token", Expiry: time.Now().Add(time.Hour)}
+	if cached {
+		conf.SetClusterAuthInformation(clusterId, "test-user", role, "some-auth-token", time.Now().Add(time.Hour))
+	}
+	configManager := config.NewLoaderFromExplicitFile(configFile)
+	if err := configManager.SaveToFile(conf, configFile, 0600); err != nil {
+		b.Fatal(err)
+	}
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		// Start each iteration with the original config, so uncached credentials stay uncached
+		if err := os.WriteFile(configFile, data, 0600); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		configManager := config.NewLoaderFromExplicitFile(configFile)
+		if err := configManager.LoadConfig(); err != nil {
+			b.Fatal(err)
+		}
+		uc := NewGetClusterCredentialsUseCase(configManager, clusterId, role).(*getClusterCredentialsUseCase)
+		uc.getExecInfo = func() (*k8s.ExecInfo, error) { return nil, nil }
+		uc.dial = dial
+		uc.out = io.Discard
+		if err := uc.Run(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetClusterCredentialsCached(b *testing.B) {
+	benchmarkClusterCredentials(b, true)
+}
+
+func BenchmarkGetClusterCredentialsUncached(b *testing.B) {
+	benchmarkClusterCredentials(b, false)
+}
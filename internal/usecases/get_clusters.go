@@ -16,11 +16,13 @@ package usecases
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"time"
 
 	"github.com/finleap-connect/monoctl/internal/config"
 	"github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/k8s"
 	"github.com/finleap-connect/monoctl/internal/output"
 	api_domain "github.com/finleap-connect/monoskope/pkg/api/domain"
 	ggrpc "google.golang.org/grpc"
@@ -45,7 +47,11 @@ func NewGetClustersUseCase(config *config.Config, outputOptions *output.OutputOp
 	if outputOptions.Wide {
 		header = append(header, "ID")
 	}
-	header = append(header, []string{"NAME", "API SERVER ADDRESS", "AGE"}...)
+	header = append(header, []string{"NAME", "API SERVER ADDRESS"}...)
+	if outputOptions.Wide {
+		header = append(header, []string{"CA SUBJECT", "FINGERPRINT", "EXPIRES"}...)
+	}
+	header = append(header, "AGE")
 	if outputOptions.ShowDeleted {
 		header = append(header, "DELETED")
 	}
@@ -101,8 +107,12 @@ func (u *getClustersUseCase) doRun(ctx context.Context) error {
 		row = append(row, []interface{}{
 			cluster.Name,
 			cluster.ApiServerAddress,
-			time.Since(cluster.Metadata.Created.AsTime()),
 		}...)
+		if u.outputOptions.Wide {
+			subject, fingerprint, expires := caBundleColumns(cluster.CaCertBundle)
+			row = append(row, subject, fingerprint, expires)
+		}
+		row = append(row, time.Since(cluster.Metadata.Created.AsTime()))
 		if u.outputOptions.ShowDeleted && cluster.Metadata.Deleted.AsTime().Unix() != 0 {
 			row = append(row, time.Since(cluster.Metadata.Deleted.AsTime()))
 		}
@@ -113,6 +123,28 @@ func (u *getClustersUseCase) doRun(ctx context.Context) error {
 	return nil
 
 }
+
+// caBundleColumns returns subject, fingerprint and expiry of the first certificate of the CA bundle.
+// If the bundle contains more certificates the number of them is appended to the subject, the expiry is the earliest one.
+func caBundleColumns(bundle []byte) (subject, fingerprint, expires string) {
+	certs, err := k8s.ParseCABundle(bundle)
+	if err != nil {
+		return "invalid", "", ""
+	}
+
+	subject = certs[0].Subject.String()
+	if len(certs) > 1 {
+		subject = fmt.Sprintf("%s (+%d)", subject, len(certs)-1)
+	}
+	notAfter := certs[0].NotAfter
+	for _, cert := range certs[1:] {
+		if cert.NotAfter.Before(notAfter) {
+			notAfter = cert.NotAfter
+		}
+	}
+	return subject, k8s.CertificateFingerprint(certs[0]), notAfter.UTC().Format(time.RFC3339)
+}
+
 func (u *getClustersUseCase) Run(ctx context.Context) error {
 	err := u.setUp(ctx)
 	if err != nil {
@@ -16,7 +16,14 @@ package usecases
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"io"
+	"math/big"
 	"time"
 
 	"github.com/finleap-connect/monoctl/internal/config"
@@ -112,4 +119,63 @@ var _ = Describe("GetCluster", func() {
 		tbl.Render()
 
 	})
+
+	It("shows the CA certificate in wide output", func() {
+		expires := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+		bundle := newTestCABundle("one-cluster-ca", expires)
+
+		subject, fingerprint, expiry := caBundleColumns(bundle)
+		Expect(subject).To(Equal("CN=one-cluster-ca"))
+		Expect(fingerprint).To(HaveLen(95))
+		Expect(expiry).To(Equal("2030-01-01T00:00:00Z"))
+
+		subject, _, _ = caBundleColumns(append(append(bundle, '\n'), bundle...))
+		Expect(subject).To(Equal("CN=one-cluster-ca (+1)"))
+
+		subject, fingerprint, _ = caBundleColumns(expectedClusterCACertBundle)
+		Expect(subject).To(Equal("invalid"))
+		Expect(fingerprint).To(BeEmpty())
+
+		conf := config.NewConfig()
+		conf.Server = expectedServer
+		gcUc := NewGetClustersUseCase(conf, &output.OutputOptions{Wide: true}).(*getClustersUseCase)
+		ctx := context.Background()
+
+		mockClient := mdom.NewMockClusterClient(mockCtrl)
+		getAllClient := mdom.NewMockCluster_GetAllClient(mockCtrl)
+		getAllClient.EXPECT().Recv().Return(&projections.Cluster{
+			Id:               expectedUUID.String(),
+			Name:             expectedName,
+			ApiServerAddress: expectedApiServerAddress,
+			CaCertBundle:     bundle,
+			Metadata: &projections.LifecycleMetadata{
+				Created: timestamppb.Now(),
+			},
+		}, nil)
+		getAllClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClient.EXPECT().GetAll(ctx, &api_commandhandler.GetAllRequest{}).Return(getAllClient, nil)
+		gcUc.client = mockClient
+
+		Expect(gcUc.doRun(ctx)).To(Succeed())
+		tbl, err := gcUc.tableFactory.ToTable()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tbl.NumLines()).To(Equal(1))
+	})
 })
+
+// newTestCABundle returns a PEM encoded self-signed CA certificate expiring at the given time
+func newTestCABundle(commonName string, expires time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              expires,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,270 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/output"
+	domApi "github.com/finleap-connect/monoskope/pkg/api/domain"
+	cmdData "github.com/finleap-connect/monoskope/pkg/api/domain/commanddata"
+	esApi "github.com/finleap-connect/monoskope/pkg/api/eventsourcing"
+	cmd "github.com/finleap-connect/monoskope/pkg/domain/commands"
+	commandTypes "github.com/finleap-connect/monoskope/pkg/domain/constants/commands"
+	mk8s "github.com/finleap-connect/monoskope/pkg/k8s"
+	"github.com/google/uuid"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"k8s.io/client-go/tools/clientcmd"
+	kapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// importClustersUseCase provides the internal use-case of creating the clusters of a kubeconfig
+type importClustersUseCase struct {
+	useCaseBase
+	kubeconfigFile string
+	contextPattern string
+	dryRun         bool
+
+	conn           *ggrpc.ClientConn
+	cHandlerClient esApi.CommandHandlerClient
+	clusterClient  domApi.ClusterClient
+	// results of the last run, sorted by the name of the cluster in the kubeconfig
+	results []*importClusterResult
+}
+
+// importClusterResult is the result of importing a cluster of the kubeconfig
+type importClusterResult struct {
+	kubeconfigName   string
+	name             string
+	apiServerAddress string
+	result           string
+	err              error
+}
+
+// NewImportClustersUseCase returns the use-case creating the clusters of the kubeconfig which are not known yet.
+// If a context pattern is given, only the clusters of the contexts matching it are imported.
+func NewImportClustersUseCase(config *config.Config, kubeconfigFile, contextPattern string, dryRun bool) UseCase {
+	useCase := &importClustersUseCase{
+		useCaseBase:    NewUseCaseBase("import-clusters", config),
+		kubeconfigFile: kubeconfigFile,
+		contextPattern: contextPattern,
+		dryRun:         dryRun,
+	}
+	return useCase
+}
+
+func (u *importClustersUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
+
+	conn, err := grpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if err != nil {
+		return err
+	}
+
+	u.conn = conn
+	u.cHandlerClient = esApi.NewCommandHandlerClient(u.conn)
+	u.clusterClient = domApi.NewClusterClient(u.conn)
+
+	u.setInitialized()
+
+	return nil
+}
+
+// selectClusters returns the names of the clusters of the kubeconfig to import
+func (u *importClustersUseCase) selectClusters(kubeConfig *kapi.Config) ([]string, error) {
+	if _, err := filepath.Match(u.contextPattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid context pattern '%s': %w", u.contextPattern, err)
+	}
+
+	selected := make(map[string]bool)
+	if u.contextPattern == "" {
+		for name := range kubeConfig.Clusters {
+			selected[name] = true
+		}
+	} else {
+		for contextName, kctx := range kubeConfig.Contexts {
+			if ok, _ := filepath.Match(u.contextPattern, contextName); ok {
+				selected[kctx.Cluster] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(selected))
+	for name := range selected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// readCABundle returns the CA bundle of the cluster, reading it from file relative to the kubeconfig if not embedded
+func (u *importClustersUseCase) readCABundle(cluster *kapi.Cluster) ([]byte, error) {
+	if len(cluster.CertificateAuthorityData) > 0 {
+		return cluster.CertificateAuthorityData, nil
+	}
+	if cluster.CertificateAuthority == "" {
+		return nil, errors.New("no certificate-authority or certificate-authority-data")
+	}
+	caFile := cluster.CertificateAuthority
+	if !filepath.IsAbs(caFile) {
+		caFile = filepath.Join(filepath.Dir(u.kubeconfigFile), caFile)
+	}
+	return os.ReadFile(caFile)
+}
+
+// importCluster creates the cluster of the kubeconfig if it does not exist yet
+func (u *importClustersUseCase) importCluster(ctx context.Context, result *importClusterResult, cluster *kapi.Cluster, names map[string]string) error {
+	name, err := mk8s.GetK8sName(result.kubeconfigName)
+	if err != nil {
+		return err
+	}
+	result.name = name
+	if other, ok := names[name]; ok {
+		return fmt.Errorf("name collides with cluster '%s' of the kubeconfig", other)
+	}
+	names[name] = result.kubeconfigName
+
+	result.apiServerAddress = cluster.Server
+	server, err := url.Parse(cluster.Server)
+	if err != nil {
+		return err
+	}
+	if !server.IsAbs() || server.Hostname() == "" {
+		return fmt.Errorf("invalid server '%s'", cluster.Server)
+	}
+
+	caCertBundle, err := u.readCABundle(cluster)
+	if err != nil {
+		return err
+	}
+	if err := validateCABundle(caCertBundle, os.Stderr); err != nil {
+		return err
+	}
+
+	if _, err := u.clusterClient.GetByName(ctx, wrapperspb.String(name)); err == nil {
+		result.result = "skipped, already exists"
+		return nil
+	} else if status.Code(err) != codes.NotFound {
+		return err
+	}
+
+	if u.dryRun {
+		result.result = "would be created"
+		return nil
+	}
+
+	// this is a create command; use nil as input, the correct ID will be contained in the reply
+	command := cmd.NewCommandWithData(uuid.Nil, commandTypes.CreateCluster, &cmdData.CreateCluster{
+		Name:             name,
+		ApiServerAddress: cluster.Server,
+		CaCertBundle:     caCertBundle,
+	})
+	if _, err := u.cHandlerClient.Execute(ctx, command); err != nil {
+		return err
+	}
+	result.result = "created"
+	return nil
+}
+
+func (u *importClustersUseCase) run(ctx context.Context) error {
+	kubeConfig, err := clientcmd.LoadFromFile(u.kubeconfigFile)
+	if err != nil {
+		return fmt.Errorf("failed loading kubeconfig '%s': %w", u.kubeconfigFile, err)
+	}
+
+	clusterNames, err := u.selectClusters(kubeConfig)
+	if err != nil {
+		return err
+	}
+	if len(clusterNames) == 0 {
+		return fmt.Errorf("no clusters to import found in '%s'", u.kubeconfigFile)
+	}
+
+	u.results = nil
+	names := make(map[string]string)
+	var failed int
+	for _, clusterName := range clusterNames {
+		result := &importClusterResult{kubeconfigName: clusterName}
+		u.results = append(u.results, result)
+
+		cluster, ok := kubeConfig.Clusters[clusterName]
+		if !ok {
+			result.err = errors.New("referenced by context but not defined")
+		} else {
+			result.err = u.importCluster(ctx, result, cluster, names)
+		}
+		if result.err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed importing %d of %d clusters", failed, len(u.results))
+	}
+	return nil
+}
+
+// printResults prints what happened to each cluster of the kubeconfig
+func (u *importClustersUseCase) printResults() error {
+	var data [][]interface{}
+	for _, result := range u.results {
+		text := result.result
+		if result.err != nil {
+			text = fmt.Sprintf("failed: %v", result.err)
+		}
+		data = append(data, []interface{}{result.kubeconfigName, result.name, result.apiServerAddress, text})
+	}
+
+	tbl, err := output.NewTableFactory().
+		SetHeader([]string{"KUBECONFIG CLUSTER", "NAME", "API SERVER ADDRESS", "RESULT"}).
+		SetData(data).
+		ToTable()
+	if err != nil {
+		return err
+	}
+	tbl.Render()
+	return nil
+}
+
+func (u *importClustersUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	err = u.run(ctx)
+	if len(u.results) > 0 {
+		if printErr := u.printResults(); printErr != nil {
+			return printErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mdom "github.com/finleap-connect/monoctl/test/mock/domain"
+	mes "github.com/finleap-connect/monoctl/test/mock/eventsourcing"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/commanddata"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	es "github.com/finleap-connect/monoskope/pkg/api/eventsourcing"
+	"github.com/finleap-connect/monoskope/pkg/api/eventsourcing/commands"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"k8s.io/client-go/tools/clientcmd"
+	kapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+var _ = Describe("ImportClusters", func() {
+	var (
+		ctx               = context.Background()
+		mockCtrl          *gomock.Controller
+		mockClusterClient *mdom.MockClusterClient
+		mockCommandClient *mes.MockCommandHandlerClient
+		kubeconfigFile    string
+		caBundle          []byte
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClusterClient = mdom.NewMockClusterClient(mockCtrl)
+		mockCommandClient = mes.NewMockCommandHandlerClient(mockCtrl)
+
+		dir, err := os.MkdirTemp("", "monoctl-import")
+		Expect(err).ToNot(HaveOccurred())
+		caBundle = newTestCABundle("cluster-ca", time.Now().Add(time.Hour))
+		Expect(os.WriteFile(filepath.Join(dir, "ca.pem"), caBundle, 0600)).To(Succeed())
+
+		kubeConfig := kapi.NewConfig()
+		kubeConfig.Clusters["prod.eu"] = &kapi.Cluster{Server: "https://api.prod-eu.example.com", CertificateAuthorityData: caBundle}
+		kubeConfig.Clusters["prod_us"] = &kapi.Cluster{Server: "https://api.prod-us.example.com", CertificateAuthority: "ca.pem"}
+		kubeConfig.Clusters["dev"] = &kapi.Cluster{Server: "https://api.dev.example.com", CertificateAuthorityData: caBundle}
+		kubeConfig.Contexts["prod-eu"] = &kapi.Context{Cluster: "prod.eu"}
+		kubeConfig.Contexts["prod-us"] = &kapi.Context{Cluster: "prod_us"}
+		kubeConfig.Contexts["dev"] = &kapi.Context{Cluster: "dev"}
+		kubeconfigFile = filepath.Join(dir, "kubeconfig")
+		Expect(clientcmd.WriteToFile(*kubeConfig, kubeconfigFile)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+		Expect(os.RemoveAll(filepath.Dir(kubeconfigFile))).To(Succeed())
+	})
+
+	newUseCase := func(contextPattern string, dryRun bool) *importClustersUseCase {
+		uc := NewImportClustersUseCase(config.NewConfig(), kubeconfigFile, contextPattern, dryRun).(*importClustersUseCase)
+		uc.clusterClient = mockClusterClient
+		uc.cHandlerClient = mockCommandClient
+		uc.setInitialized()
+		return uc
+	}
+
+	notFound := status.Error(codes.NotFound, "cluster not found")
+
+	It("creates the clusters of the matching contexts which do not exist yet", func() {
+		mockClusterClient.EXPECT().GetByName(ctx, wrapperspb.String("prod-eu")).Return(nil, notFound)
+		mockClusterClient.EXPECT().GetByName(ctx, wrapperspb.String("prod-us")).Return(&projections.Cluster{Id: uuid.New().String(), Name: "prod-us"}, nil)
+		mockCommandClient.EXPECT().Execute(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, command *commands.Command, _ ...grpc.CallOption) (*es.CommandReply, error) {
+			data := new(commanddata.CreateCluster)
+			Expect(command.Data.UnmarshalTo(data)).To(Succeed())
+			Expect(data.Name).To(Equal("prod-eu"))
+			Expect(data.ApiServerAddress).To(Equal("https://api.prod-eu.example.com"))
+			Expect(data.CaCertBundle).To(Equal(caBundle))
+			return &es.CommandReply{AggregateId: uuid.New().String()}, nil
+		})
+
+		uc := newUseCase("prod-*", false)
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(uc.results).To(HaveLen(2))
+		Expect(uc.results[0].result).To(Equal("created"))
+		Expect(uc.results[1].name).To(Equal("prod-us"))
+		Expect(uc.results[1].result).To(Equal("skipped, already exists"))
+	})
+
+	It("only shows what would be created in dry run", func() {
+		mockClusterClient.EXPECT().GetByName(ctx, gomock.Any()).Return(nil, notFound).Times(3)
+
+		uc := newUseCase("", true)
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(uc.results).To(HaveLen(3))
+		for _, result := range uc.results {
+			Expect(result.result).To(Equal("would be created"))
+		}
+	})
+
+	It("reports clusters which can not be imported", func() {
+		kubeConfig, err := clientcmd.LoadFromFile(kubeconfigFile)
+		Expect(err).ToNot(HaveOccurred())
+		kubeConfig.Clusters["dev"].CertificateAuthorityData = []byte("This should be a certificate")
+		Expect(clientcmd.WriteToFile(*kubeConfig, kubeconfigFile)).To(Succeed())
+
+		uc := newUseCase("dev", true)
+		Expect(uc.Run(ctx)).To(MatchError("failed importing 1 of 1 clusters"))
+		Expect(uc.results[0].err).To(HaveOccurred())
+	})
+})
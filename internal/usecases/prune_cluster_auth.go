@@ -0,0 +1,135 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	ggrpc "google.golang.org/grpc"
+)
+
+// pruneClusterAuthUseCase provides the internal use-case of removing cached cluster tokens which are not usable anymore
+type pruneClusterAuthUseCase struct {
+	useCaseBase
+	conn                *ggrpc.ClientConn
+	configManager       *config.ClientConfigManager
+	clusterAccessClient api.ClusterAccessClient
+	out                 io.Writer
+}
+
+// NewPruneClusterAuthUseCase returns the use-case removing the cached tokens of the user for clusters and roles the user has no
+// access to anymore and expired ones. Tokens of other users are kept.
+func NewPruneClusterAuthUseCase(configManager *config.ClientConfigManager) UseCase {
+	useCase := &pruneClusterAuthUseCase{
+		useCaseBase:   NewUseCaseBase("prune-cluster-auth", configManager.GetConfig()),
+		configManager: configManager,
+		out:           os.Stdout,
+	}
+	return useCase
+}
+
+func (u *pruneClusterAuthUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
+
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if err != nil {
+		return err
+	}
+
+	u.conn = conn
+	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
+
+	u.setInitialized()
+
+	return nil
+}
+
+func (u *pruneClusterAuthUseCase) run(ctx context.Context) error {
+	clusterAccesses, err := getClusterAccesses(ctx, u.clusterAccessClient)
+	if err != nil {
+		return err
+	}
+	clusterNames := make(map[string]string)
+	accessible := make(map[string]bool)
+	for _, clusterAccess := range clusterAccesses {
+		clusterNames[clusterAccess.Cluster.Id] = clusterAccess.Cluster.Name
+		for _, clusterRole := range clusterAccess.ClusterRoles {
+			accessible[credentialKey(clusterAccess.Cluster.Id, clusterRole.Role)] = true
+		}
+	}
+
+	keys := make([]string, 0, len(u.config.ClusterAuthInformation))
+	for key := range u.config.ClusterAuthInformation {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var removed int
+	for _, key := range keys {
+		clusterId, username, role, ok := config.ParseClusterAuthKey(key)
+		var reason string
+		switch {
+		case !ok:
+			reason = "invalid entry"
+		case username != u.config.AuthInformation.Username:
+			// The access of other users can not be checked with the current session.
+			continue
+		case !accessible[credentialKey(clusterId, role)]:
+			reason = "no access anymore"
+		case !u.config.ClusterAuthInformation[key].IsValidExact():
+			reason = "expired"
+		default:
+			continue
+		}
+
+		if err := u.config.RemoveClusterAuthInformation(key); err != nil {
+			return err
+		}
+		removed++
+
+		cluster := clusterId
+		if name, ok := clusterNames[clusterId]; ok {
+			cluster = name
+		}
+		fmt.Fprintf(u.out, "Removed token for role '%s' on cluster '%s': %s.\n", role, cluster, reason)
+	}
+
+	if removed == 0 {
+		fmt.Fprintln(u.out, "No tokens to remove.")
+		return nil
+	}
+	return u.configManager.SaveConfig()
+}
+
+func (u *pruneClusterAuthUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	return u.run(ctx)
+}
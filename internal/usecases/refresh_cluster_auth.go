@@ -0,0 +1,183 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/output"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	apiGateway "github.com/finleap-connect/monoskope/pkg/api/gateway"
+	"golang.org/x/sync/errgroup"
+	ggrpc "google.golang.org/grpc"
+)
+
+// DefaultClusterAuthConcurrency is the default number of cluster tokens requested in parallel
+const DefaultClusterAuthConcurrency = 5
+
+// refreshClusterAuthUseCase provides the internal use-case of fetching the tokens of all accessible clusters and roles
+type refreshClusterAuthUseCase struct {
+	useCaseBase
+	conn                *ggrpc.ClientConn
+	configManager       *config.ClientConfigManager
+	clusterAccessClient api.ClusterAccessClient
+	clusterAuthClient   apiGateway.ClusterAuthClient
+	concurrency         int
+	// results of the last run, sorted by cluster name and role
+	results []*clusterAuthResult
+}
+
+// clusterAuthResult is the result of fetching the token of a role on a cluster
+type clusterAuthResult struct {
+	clusterName string
+	role        string
+	expiry      time.Time
+	err         error
+}
+
+// NewRefreshClusterAuthUseCase returns the use-case fetching the tokens of all accessible clusters and roles,
+// requesting at most concurrency tokens in parallel.
+func NewRefreshClusterAuthUseCase(configManager *config.ClientConfigManager, concurrency int) UseCase {
+	if concurrency < 1 {
+		concurrency = DefaultClusterAuthConcurrency
+	}
+	useCase := &refreshClusterAuthUseCase{
+		useCaseBase:   NewUseCaseBase("refresh-cluster-auth", configManager.GetConfig()),
+		configManager: configManager,
+		concurrency:   concurrency,
+	}
+	return useCase
+}
+
+func (u *refreshClusterAuthUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
+
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if err != nil {
+		return err
+	}
+
+	u.conn = conn
+	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
+	u.clusterAuthClient = apiGateway.NewClusterAuthClient(u.conn)
+
+	u.setInitialized()
+
+	return nil
+}
+
+func (u *refreshClusterAuthUseCase) run(ctx context.Context) error {
+	clusterAccesses, err := getClusterAccesses(ctx, u.clusterAccessClient)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(u.concurrency)
+	u.results = nil
+	for _, clusterAccess := range sortedClusterAccesses(clusterAccesses) {
+		for _, clusterRole := range clusterAccess.ClusterRoles {
+			result := &clusterAuthResult{clusterName: clusterAccess.Cluster.Name, role: clusterRole.Role}
+			u.results = append(u.results, result)
+			clusterId := clusterAccess.Cluster.Id
+
+			group.Go(func() error {
+				// A failing cluster does not stop the others, the errors are reported per cluster
+				response, err := u.clusterAuthClient.GetAuthToken(groupCtx, &apiGateway.ClusterAuthTokenRequest{
+					ClusterId: clusterId,
+					Role:      result.role,
+				})
+				if err != nil {
+					result.err = err
+					return nil
+				}
+				result.expiry = response.Expiry.AsTime()
+
+				mu.Lock()
+				defer mu.Unlock()
+				u.config.SetClusterAuthInformation(clusterId, u.config.AuthInformation.Username, result.role, response.AccessToken, result.expiry)
+				return nil
+			})
+		}
+	}
+	if err := group.Wait(); err != nil {
+		return err
+	}
+
+	if err := u.configManager.SaveConfig(); err != nil {
+		return err
+	}
+
+	var failed int
+	for _, result := range u.results {
+		if result.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed fetching %d of %d cluster tokens", failed, len(u.results))
+	}
+	return nil
+}
+
+// printResults prints the expiry of the fetched tokens or why fetching them failed
+func (u *refreshClusterAuthUseCase) printResults() error {
+	var data [][]interface{}
+	for _, result := range u.results {
+		expiry, status := "", "refreshed"
+		if result.err != nil {
+			status = fmt.Sprintf("failed: %v", result.err)
+		} else {
+			expiry = result.expiry.Local().Format(time.RFC3339)
+		}
+		data = append(data, []interface{}{result.clusterName, result.role, expiry, status})
+	}
+
+	tbl, err := output.NewTableFactory().
+		SetHeader([]string{"CLUSTER", "ROLE", "EXPIRY", "STATUS"}).
+		SetData(data).
+		ToTable()
+	if err != nil {
+		return err
+	}
+	tbl.Render()
+	return nil
+}
+
+func (u *refreshClusterAuthUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	err = u.run(ctx)
+	if len(u.results) > 0 {
+		if printErr := u.printResults(); printErr != nil {
+			return printErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,354 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/output"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/audit"
+	cmdData "github.com/finleap-connect/monoskope/pkg/api/domain/commanddata"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	esApi "github.com/finleap-connect/monoskope/pkg/api/eventsourcing"
+	esCommands "github.com/finleap-connect/monoskope/pkg/api/eventsourcing/commands"
+	cmd "github.com/finleap-connect/monoskope/pkg/domain/commands"
+	commandTypes "github.com/finleap-connect/monoskope/pkg/domain/constants/commands"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/events"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/scopes"
+	"github.com/google/uuid"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// DefaultRestoreBefore is how long before the deletion of an entity the rolebindings and cluster access
+// it had are looked up in the audit log, so that dependents deleted along with it are restored as well
+const DefaultRestoreBefore = time.Minute
+
+var (
+	// auditQuotedRegex matches the quoted values of the details of audit log events
+	auditQuotedRegex = regexp.MustCompile(`“([^“”]*)[“”]`)
+	// overviewSystemRoleRegex matches a line of the roles of a users overview, e.g. "- system admin"
+	overviewSystemRoleRegex = regexp.MustCompile(`^- (\S+) (\S+)$`)
+	// overviewTenantRoleRegex matches a line of the tenants of a users overview, e.g. "- my-tenant (admin)"
+	overviewTenantRoleRegex = regexp.MustCompile(`^- (.+) \((\S+)\)$`)
+)
+
+// restoreStep is a single command restoring an entity or reattaching a dependent of it
+type restoreStep struct {
+	kind    string
+	name    string
+	command *esCommands.Command
+	err     error
+}
+
+// restoreTarget is a deleted entity, it is re-created by the create step and its dependents are reattached afterwards
+type restoreTarget struct {
+	create *restoreStep
+	// id before the deletion, empty if the audit log does not show it
+	id      string
+	deleted time.Time
+	// reattach is nil if the entity has no dependents
+	reattach func(ctx context.Context, id string) ([]*restoreStep, error)
+}
+
+// restoreUseCase provides the internal use-case of restoring a deleted entity.
+// The m8 control plane has no undelete, the entity is re-created with a new id
+// and its rolebindings and cluster access are reattached as found in the audit log.
+type restoreUseCase struct {
+	useCaseBase
+	conn             *ggrpc.ClientConn
+	userClient       api.UserClient
+	tenantClient     api.TenantClient
+	clusterClient    api.ClusterClient
+	auditLogClient   api.AuditLogClient
+	cmdHandlerClient esApi.CommandHandlerClient
+	kind             string
+	nameOrId         string
+	before           time.Duration
+	find             func(ctx context.Context) (*restoreTarget, error)
+	out              io.Writer
+}
+
+func newRestoreUseCase(config *config.Config, kind, nameOrId string, before time.Duration) *restoreUseCase {
+	return &restoreUseCase{
+		useCaseBase: NewUseCaseBase("restore-"+kind, config),
+		kind:        kind,
+		nameOrId:    nameOrId,
+		before:      before,
+		out:         os.Stdout,
+	}
+}
+
+func (u *restoreUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
+
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if err != nil {
+		return err
+	}
+
+	u.conn = conn
+	u.userClient = api.NewUserClient(u.conn)
+	u.tenantClient = api.NewTenantClient(u.conn)
+	u.clusterClient = api.NewClusterClient(u.conn)
+	u.auditLogClient = api.NewAuditLogClient(u.conn)
+	u.cmdHandlerClient = esApi.NewCommandHandlerClient(u.conn)
+	u.setInitialized()
+
+	return nil
+}
+
+// isDeleted returns if the metadata has a deletion timestamp
+func isDeleted(metadata *projections.LifecycleMetadata) bool {
+	return metadata != nil && describeTime(metadata.Deleted) != nil
+}
+
+// restorePoint returns the time the dependents of the entity are looked up at
+func (u *restoreUseCase) restorePoint(target *restoreTarget) time.Time {
+	return target.deleted.Add(-u.before)
+}
+
+// userOverviews returns the overview of all users existing at the time given
+func (u *restoreUseCase) userOverviews(ctx context.Context, at time.Time) ([]*audit.UserOverview, error) {
+	stream, err := u.auditLogClient.GetUsersOverview(ctx, &api.GetUsersOverviewRequest{Timestamp: timestamppb.New(at)})
+	if err != nil {
+		return nil, err
+	}
+
+	var overviews []*audit.UserOverview
+	for {
+		// Read next
+		overview, err := stream.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+		overviews = append(overviews, overview)
+	}
+	return overviews, nil
+}
+
+// overviewRole is a role of a user as shown by the users overview of the audit log
+type overviewRole struct {
+	scope  string
+	role   string
+	tenant string
+}
+
+// parseOverviewRoles returns the system roles and tenant roles of the users overview
+func parseOverviewRoles(overview *audit.UserOverview) []*overviewRole {
+	var result []*overviewRole
+	for _, line := range strings.Split(overview.Roles, "\n") {
+		match := overviewSystemRoleRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if match != nil && match[1] == string(scopes.System) {
+			result = append(result, &overviewRole{scope: match[1], role: match[2]})
+		}
+	}
+	for _, line := range strings.Split(overview.Tenants, "\n") {
+		match := overviewTenantRoleRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if match != nil {
+			result = append(result, &overviewRole{scope: string(scopes.Tenant), role: match[2], tenant: match[1]})
+		}
+	}
+	return result
+}
+
+// tenantClusterAccess is a tenant having access to a cluster, by their names
+type tenantClusterAccess struct {
+	tenant  string
+	cluster string
+}
+
+// previousClusterAccess replays the tenant-cluster binding events of the audit log within the range
+// and returns the bindings existing at its end
+func (u *restoreUseCase) previousClusterAccess(ctx context.Context, from, to time.Time) ([]tenantClusterAccess, error) {
+	stream, err := u.auditLogClient.GetByDateRange(ctx, &api.GetAuditLogByDateRangeRequest{
+		MinTimestamp: timestamppb.New(from),
+		MaxTimestamp: timestamppb.New(to),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []tenantClusterAccess
+	for {
+		// Read next
+		event, err := stream.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		values := auditQuotedRegex.FindAllStringSubmatch(event.Details, -1)
+		if len(values) != 3 {
+			continue
+		}
+		switch event.EventType {
+		case events.TenantClusterBindingCreated.String():
+			// “issuer“ granted tenant “tenant“ access to cluster “cluster”
+			result = append(result, tenantClusterAccess{tenant: values[1][1], cluster: values[2][1]})
+		case events.TenantClusterBindingDeleted.String():
+			// “issuer“ revoked access to cluster “cluster“ for tenant “tenant“
+			revoked := tenantClusterAccess{tenant: values[2][1], cluster: values[1][1]}
+			for i, access := range result {
+				if access == revoked {
+					result = append(result[:i], result[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// createRoleBindingStep returns the step creating the rolebinding of the user, the resource is the id of the tenant if scoped to one
+func createRoleBindingStep(user *projections.User, role, scope, resource, resourceName string) *restoreStep {
+	name := fmt.Sprintf("%s of %s", role, user.Email)
+	if resourceName != "" {
+		name = fmt.Sprintf("%s on %s '%s'", name, scope, resourceName)
+	}
+	return &restoreStep{
+		kind: deleteKindRoleBinding,
+		name: name,
+		command: cmd.NewCommandWithData(uuid.Nil, commandTypes.CreateUserRoleBinding, &cmdData.CreateUserRoleBindingCommandData{
+			UserId:   user.Id,
+			Role:     role,
+			Scope:    scope,
+			Resource: wrapperspb.String(resource),
+		}),
+	}
+}
+
+// createClusterAccessStep returns the step granting the tenant access to the cluster
+func createClusterAccessStep(tenant *projections.Tenant, cluster *projections.Cluster) *restoreStep {
+	return &restoreStep{
+		kind: deleteKindClusterAccess,
+		name: fmt.Sprintf("tenant '%s' on cluster '%s'", tenant.Name, cluster.Name),
+		command: cmd.NewCommandWithData(uuid.Nil, commandTypes.CreateTenantClusterBinding, &cmdData.CreateTenantClusterBindingCommandData{
+			TenantId:  tenant.Id,
+			ClusterId: cluster.Id,
+		}),
+	}
+}
+
+// failedStep returns a step which can not be executed as a dependent could not be resolved
+func failedStep(kind, name string, err error) *restoreStep {
+	return &restoreStep{kind: kind, name: name, err: err}
+}
+
+// renderRestoreSteps writes the steps with their result to out
+func renderRestoreSteps(out io.Writer, steps []*restoreStep) error {
+	var data [][]interface{}
+	for i, step := range steps {
+		result := "restored"
+		if step.err != nil {
+			result = fmt.Sprintf("failed: %v", step.err)
+		}
+		// the step keeps the order in which the entities are restored
+		data = append(data, []interface{}{i + 1, step.kind, step.name, result})
+	}
+
+	tbl, err := output.NewTableFactory().
+		SetOutput(out).
+		SetHeader([]string{"#", "KIND", "NAME", "RESULT"}).
+		SetData(data).
+		ToTable()
+	if err != nil {
+		return err
+	}
+	tbl.Render()
+	return nil
+}
+
+// restore re-creates the entity and reattaches its dependents
+func (u *restoreUseCase) restore(ctx context.Context, target *restoreTarget) error {
+	reply, err := u.cmdHandlerClient.Execute(ctx, target.create.command)
+	if err != nil {
+		target.create.err = err
+		return fmt.Errorf("failed restoring %s: %w", target.create.name, err)
+	}
+	if target.id == "" {
+		fmt.Fprintf(u.out, "Restored %s %s with new id %s.\n", target.create.kind, target.create.name, reply.AggregateId)
+	} else {
+		fmt.Fprintf(u.out, "Restored %s with new id %s, the id before deletion was %s.\n", target.create.name, reply.AggregateId, target.id)
+	}
+	if target.reattach == nil {
+		return nil
+	}
+
+	steps, err := target.reattach(ctx, reply.AggregateId)
+	if err != nil {
+		return fmt.Errorf("failed looking up what to reattach to %s: %w", target.create.name, err)
+	}
+	if len(steps) == 0 {
+		fmt.Fprintf(u.out, "Nothing to reattach as of %s.\n", u.restorePoint(target).Local().Format(time.RFC3339))
+		return nil
+	}
+
+	var failed int
+	for _, step := range steps {
+		if step.err == nil {
+			_, step.err = u.cmdHandlerClient.Execute(ctx, step.command)
+		}
+		if step.err != nil {
+			failed++
+		}
+	}
+
+	fmt.Fprintf(u.out, "Reattached as of %s:\n", u.restorePoint(target).Local().Format(time.RFC3339))
+	if err := renderRestoreSteps(u.out, steps); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed reattaching %d of %d rolebindings and cluster access", failed, len(steps))
+	}
+	return nil
+}
+
+func (u *restoreUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	target, err := u.find(ctx)
+	if err != nil {
+		return err
+	}
+	return u.restore(ctx, target)
+}
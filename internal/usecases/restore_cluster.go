@@ -0,0 +1,118 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	cmdData "github.com/finleap-connect/monoskope/pkg/api/domain/commanddata"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	cmd "github.com/finleap-connect/monoskope/pkg/domain/commands"
+	commandTypes "github.com/finleap-connect/monoskope/pkg/domain/constants/commands"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// NewRestoreClusterUseCase returns the use-case re-creating the deleted cluster with the given name or id
+// and reattaching the tenants which had access to it the given duration before the deletion.
+func NewRestoreClusterUseCase(config *config.Config, nameOrId string, before time.Duration) UseCase {
+	useCase := newRestoreUseCase(config, "cluster", nameOrId, before)
+	useCase.find = useCase.findCluster
+	return useCase
+}
+
+// findDeletedCluster returns the most recently deleted cluster with the name or id
+func (u *restoreUseCase) findDeletedCluster(ctx context.Context) (*projections.Cluster, error) {
+	stream, err := u.clusterClient.GetAll(ctx, &api.GetAllRequest{IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted *projections.Cluster
+	for {
+		// Read next
+		cluster, err := stream.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		if cluster.Name != u.nameOrId && cluster.Id != u.nameOrId {
+			continue
+		}
+		if !isDeleted(cluster.Metadata) {
+			return nil, fmt.Errorf("cluster '%s' is not deleted", u.nameOrId)
+		}
+		if deleted == nil || cluster.Metadata.Deleted.AsTime().After(deleted.Metadata.Deleted.AsTime()) {
+			deleted = cluster
+		}
+	}
+	if deleted == nil {
+		return nil, fmt.Errorf("no deleted cluster '%s' found", u.nameOrId)
+	}
+	return deleted, nil
+}
+
+func (u *restoreUseCase) findCluster(ctx context.Context) (*restoreTarget, error) {
+	cluster, err := u.findDeletedCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &restoreTarget{
+		create: &restoreStep{
+			kind: "cluster",
+			name: fmt.Sprintf("cluster '%s'", cluster.Name),
+			command: cmd.NewCommandWithData(uuid.Nil, commandTypes.CreateCluster, &cmdData.CreateCluster{
+				Name:             cluster.Name,
+				ApiServerAddress: cluster.ApiServerAddress,
+				CaCertBundle:     cluster.CaCertBundle,
+			}),
+		},
+		id:      cluster.Id,
+		deleted: cluster.Metadata.Deleted.AsTime(),
+	}
+	target.reattach = func(ctx context.Context, id string) ([]*restoreStep, error) {
+		restored := &projections.Cluster{Id: id, Name: cluster.Name}
+
+		accesses, err := u.previousClusterAccess(ctx, cluster.Metadata.Created.AsTime(), u.restorePoint(target))
+		if err != nil {
+			return nil, err
+		}
+		var steps []*restoreStep
+		for _, access := range accesses {
+			if access.cluster != cluster.Name {
+				continue
+			}
+			tenant, err := u.tenantClient.GetByName(ctx, wrapperspb.String(access.tenant))
+			if err != nil {
+				steps = append(steps, failedStep(deleteKindClusterAccess, fmt.Sprintf("tenant '%s' on cluster '%s'", access.tenant, cluster.Name), err))
+				continue
+			}
+			steps = append(steps, createClusterAccessStep(tenant, restored))
+		}
+		return steps, nil
+	}
+	return target, nil
+}
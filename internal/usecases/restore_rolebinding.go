@@ -0,0 +1,181 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/audit"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/events"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/scopes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// NewRestoreRoleBindingUseCase returns the use-case re-creating the most recently deleted rolebinding of the user
+// with the given role and scope. The audit log does not record the tenant of a rolebinding, it is looked up in
+// the overview of the user the given duration before the deletion unless given.
+func NewRestoreRoleBindingUseCase(config *config.Config, email, role, scope, tenant string, before time.Duration) UseCase {
+	useCase := newRestoreUseCase(config, deleteKindRoleBinding, email, before)
+	useCase.find = func(ctx context.Context) (*restoreTarget, error) {
+		return useCase.findRoleBinding(ctx, role, scope, tenant)
+	}
+	return useCase
+}
+
+// findRoleBindingDeletion replays the audit log since the user has been created and returns the most recent
+// deletion of the rolebinding of the user with the role and scope
+func (u *restoreUseCase) findRoleBindingDeletion(ctx context.Context, user *projections.User, role, scope string) (*audit.HumanReadableEvent, error) {
+	stream, err := u.auditLogClient.GetByDateRange(ctx, &api.GetAuditLogByDateRangeRequest{
+		MinTimestamp: user.Metadata.GetCreated(),
+		MaxTimestamp: timestamppb.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var deletion *audit.HumanReadableEvent
+	for {
+		// Read next
+		event, err := stream.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		if event.EventType != events.UserRoleBindingDeleted.String() {
+			continue
+		}
+		// “issuer“ removed the role “role“ for scope “scope“ from user “email“
+		values := auditQuotedRegex.FindAllStringSubmatch(event.Details, -1)
+		if len(values) != 4 || values[1][1] != role || values[2][1] != scope || values[3][1] != user.Email {
+			continue
+		}
+		if deletion == nil || event.Timestamp.AsTime().After(deletion.Timestamp.AsTime()) {
+			deletion = event
+		}
+	}
+	if deletion == nil {
+		return nil, fmt.Errorf("no deleted rolebinding %s of %s in scope '%s' found", role, user.Email, scope)
+	}
+	return deletion, nil
+}
+
+// hasRoleBinding returns if the user currently has the role in the scope on the resource
+func hasRoleBinding(user *projections.User, role, scope, resource string) bool {
+	for _, binding := range user.Roles {
+		if binding.Role == role && binding.Scope == scope && binding.Resource == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// findRoleBindingTenant returns the tenant the user had the role on before the deletion and has no longer
+func (u *restoreUseCase) findRoleBindingTenant(ctx context.Context, user *projections.User, role, tenantName string, at time.Time) (*projections.Tenant, error) {
+	overviews, err := u.userOverviews(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*projections.Tenant
+	for _, overview := range overviews {
+		if overview.Email != user.Email {
+			continue
+		}
+		for _, previous := range parseOverviewRoles(overview) {
+			if previous.scope != string(scopes.Tenant) || previous.role != role {
+				continue
+			}
+			if tenantName != "" && previous.tenant != tenantName {
+				continue
+			}
+			tenant, err := u.tenantClient.GetByName(ctx, wrapperspb.String(previous.tenant))
+			if err != nil {
+				return nil, fmt.Errorf("failed looking up tenant '%s': %w", previous.tenant, err)
+			}
+			if !hasRoleBinding(user, role, string(scopes.Tenant), tenant.Id) {
+				candidates = append(candidates, tenant)
+			}
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		if tenantName != "" {
+			return nil, fmt.Errorf("%s had no rolebinding %s on tenant '%s' which has been deleted since", user.Email, role, tenantName)
+		}
+		return nil, fmt.Errorf("%s had no rolebinding %s on a tenant which has been deleted since", user.Email, role)
+	case 1:
+		return candidates[0], nil
+	default:
+		var names []string
+		for _, tenant := range candidates {
+			names = append(names, tenant.Name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("the rolebinding %s of %s was on one of the tenants %s, choose with --tenant", role, user.Email, strings.Join(names, ", "))
+	}
+}
+
+func (u *restoreUseCase) findRoleBinding(ctx context.Context, role, scope, tenantName string) (*restoreTarget, error) {
+	if scope != string(scopes.System) && scope != string(scopes.Tenant) {
+		return nil, fmt.Errorf("scope '%s' is not implemented", scope)
+	}
+	if tenantName != "" && scope != string(scopes.Tenant) {
+		return nil, fmt.Errorf("a tenant can only be given for rolebindings in scope '%s'", scopes.Tenant)
+	}
+
+	user, err := u.userClient.GetByEmail(ctx, wrapperspb.String(u.nameOrId))
+	if err != nil {
+		return nil, err
+	}
+	if isDeleted(user.Metadata) {
+		return nil, fmt.Errorf("user '%s' is deleted, restore the user instead", user.Email)
+	}
+
+	deletion, err := u.findRoleBindingDeletion(ctx, user, role, scope)
+	if err != nil {
+		return nil, err
+	}
+	target := &restoreTarget{deleted: deletion.Timestamp.AsTime()}
+
+	if scope == string(scopes.System) {
+		if hasRoleBinding(user, role, scope, "") {
+			return nil, fmt.Errorf("%s has the rolebinding %s in scope '%s' already", user.Email, role, scope)
+		}
+		target.create = createRoleBindingStep(user, role, scope, "", "")
+		return target, nil
+	}
+
+	tenant, err := u.findRoleBindingTenant(ctx, user, role, tenantName, u.restorePoint(target))
+	if err != nil {
+		return nil, err
+	}
+	target.create = createRoleBindingStep(user, role, scope, tenant.Id, tenant.Name)
+	return target, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	cmdData "github.com/finleap-connect/monoskope/pkg/api/domain/commanddata"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	cmd "github.com/finleap-connect/monoskope/pkg/domain/commands"
+	commandTypes "github.com/finleap-connect/monoskope/pkg/domain/constants/commands"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/scopes"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// NewRestoreTenantUseCase returns the use-case re-creating the deleted tenant with the given name or id and
+// reattaching the rolebindings of its members and its cluster access as of the given duration before the deletion.
+func NewRestoreTenantUseCase(config *config.Config, nameOrId string, before time.Duration) UseCase {
+	useCase := newRestoreUseCase(config, "tenant", nameOrId, before)
+	useCase.find = useCase.findTenant
+	return useCase
+}
+
+// findDeletedTenant returns the most recently deleted tenant with the name or id
+func (u *restoreUseCase) findDeletedTenant(ctx context.Context) (*projections.Tenant, error) {
+	stream, err := u.tenantClient.GetAll(ctx, &api.GetAllRequest{IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted *projections.Tenant
+	for {
+		// Read next
+		tenant, err := stream.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		if tenant.Name != u.nameOrId && tenant.Id != u.nameOrId {
+			continue
+		}
+		if !isDeleted(tenant.Metadata) {
+			return nil, fmt.Errorf("tenant '%s' is not deleted", u.nameOrId)
+		}
+		if deleted == nil || tenant.Metadata.Deleted.AsTime().After(deleted.Metadata.Deleted.AsTime()) {
+			deleted = tenant
+		}
+	}
+	if deleted == nil {
+		return nil, fmt.Errorf("no deleted tenant '%s' found", u.nameOrId)
+	}
+	return deleted, nil
+}
+
+func (u *restoreUseCase) findTenant(ctx context.Context) (*restoreTarget, error) {
+	tenant, err := u.findDeletedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &restoreTarget{
+		create: &restoreStep{
+			kind: "tenant",
+			name: fmt.Sprintf("tenant '%s'", tenant.Name),
+			command: cmd.NewCommandWithData(uuid.Nil, commandTypes.CreateTenant, &cmdData.CreateTenantCommandData{
+				Name:   tenant.Name,
+				Prefix: tenant.Prefix,
+			}),
+		},
+		id:      tenant.Id,
+		deleted: tenant.Metadata.Deleted.AsTime(),
+	}
+	target.reattach = func(ctx context.Context, id string) ([]*restoreStep, error) {
+		restored := &projections.Tenant{Id: id, Name: tenant.Name, Prefix: tenant.Prefix}
+
+		overviews, err := u.userOverviews(ctx, u.restorePoint(target))
+		if err != nil {
+			return nil, err
+		}
+		var steps []*restoreStep
+		for _, overview := range overviews {
+			for _, role := range parseOverviewRoles(overview) {
+				if role.scope != string(scopes.Tenant) || role.tenant != tenant.Name {
+					continue
+				}
+				user, err := u.userClient.GetByEmail(ctx, wrapperspb.String(overview.Email))
+				if err != nil {
+					steps = append(steps, failedStep(deleteKindRoleBinding, fmt.Sprintf("%s of %s on tenant '%s'", role.role, overview.Email, tenant.Name), err))
+					continue
+				}
+				steps = append(steps, createRoleBindingStep(user, role.role, role.scope, restored.Id, restored.Name))
+			}
+		}
+
+		accesses, err := u.previousClusterAccess(ctx, tenant.Metadata.Created.AsTime(), u.restorePoint(target))
+		if err != nil {
+			return nil, err
+		}
+		for _, access := range accesses {
+			if access.tenant != tenant.Name {
+				continue
+			}
+			cluster, err := u.clusterClient.GetByName(ctx, wrapperspb.String(access.cluster))
+			if err != nil {
+				steps = append(steps, failedStep(deleteKindClusterAccess, fmt.Sprintf("tenant '%s' on cluster '%s'", tenant.Name, access.cluster), err))
+				continue
+			}
+			steps = append(steps, createClusterAccessStep(restored, cluster))
+		}
+		return steps, nil
+	}
+	return target, nil
+}
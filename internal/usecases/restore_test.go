@@ -0,0 +1,331 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mdom "github.com/finleap-connect/monoctl/test/mock/domain"
+	mes "github.com/finleap-connect/monoctl/test/mock/eventsourcing"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/audit"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/commanddata"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	es "github.com/finleap-connect/monoskope/pkg/api/eventsourcing"
+	"github.com/finleap-connect/monoskope/pkg/api/eventsourcing/commands"
+	commandTypes "github.com/finleap-connect/monoskope/pkg/domain/constants/commands"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/events"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var _ = Describe("Restore", func() {
+	var (
+		mockCtrl             *gomock.Controller
+		mockUserClient       *mdom.MockUserClient
+		mockTenantClient     *mdom.MockTenantClient
+		mockClusterClient    *mdom.MockClusterClient
+		mockAuditLogClient   *mdom.MockAuditLogClient
+		mockCmdHandlerClient *mes.MockCommandHandlerClient
+		executed             []*commands.Command
+	)
+
+	var (
+		ctx       = context.Background()
+		conf      = config.NewConfig()
+		created   = time.Now().Add(-24 * time.Hour)
+		deletedAt = time.Now().Add(-time.Hour)
+		newId     = uuid.New().String()
+
+		expectedTenant = &projections.Tenant{
+			Id:   uuid.New().String(),
+			Name: "the-tenant",
+		}
+		expectedCluster = &projections.Cluster{
+			Id:   uuid.New().String(),
+			Name: "the-cluster",
+		}
+		deletedMetadata = &projections.LifecycleMetadata{
+			Created: timestamppb.New(created),
+			Deleted: timestamppb.New(deletedAt),
+		}
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockUserClient = mdom.NewMockUserClient(mockCtrl)
+		mockTenantClient = mdom.NewMockTenantClient(mockCtrl)
+		mockClusterClient = mdom.NewMockClusterClient(mockCtrl)
+		mockAuditLogClient = mdom.NewMockAuditLogClient(mockCtrl)
+		mockCmdHandlerClient = mes.NewMockCommandHandlerClient(mockCtrl)
+
+		executed = nil
+		mockCmdHandlerClient.EXPECT().Execute(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, command *commands.Command, _ ...grpc.CallOption) (*es.CommandReply, error) {
+			executed = append(executed, command)
+			return &es.CommandReply{AggregateId: newId}, nil
+		}).AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	injectMocks := func(uc UseCase) *restoreUseCase {
+		useCase := uc.(*restoreUseCase)
+		useCase.userClient = mockUserClient
+		useCase.tenantClient = mockTenantClient
+		useCase.clusterClient = mockClusterClient
+		useCase.auditLogClient = mockAuditLogClient
+		useCase.cmdHandlerClient = mockCmdHandlerClient
+		useCase.out = new(bytes.Buffer)
+		useCase.setInitialized()
+		return useCase
+	}
+
+	expectOverviews := func(overviews ...*audit.UserOverview) {
+		stream := mdom.NewMockAuditLog_GetUsersOverviewClient(mockCtrl)
+		for _, overview := range overviews {
+			stream.EXPECT().Recv().Return(overview, nil)
+		}
+		stream.EXPECT().Recv().Return(nil, io.EOF)
+		mockAuditLogClient.EXPECT().GetUsersOverview(ctx, gomock.Any()).Return(stream, nil)
+	}
+
+	expectBindingEvents := func(events ...*audit.HumanReadableEvent) {
+		stream := mdom.NewMockAuditLog_GetByDateRangeClient(mockCtrl)
+		for _, event := range events {
+			stream.EXPECT().Recv().Return(event, nil)
+		}
+		stream.EXPECT().Recv().Return(nil, io.EOF)
+		mockAuditLogClient.EXPECT().GetByDateRange(ctx, gomock.Any()).Return(stream, nil)
+	}
+
+	It("re-creates a deleted user and reattaches its rolebindings", func() {
+		user := &projections.User{
+			Id:       uuid.New().String(),
+			Name:     "Jane Doe",
+			Email:    "jane.doe@monoskope.io",
+			Metadata: deletedMetadata,
+		}
+		users := mdom.NewMockUser_GetAllClient(mockCtrl)
+		users.EXPECT().Recv().Return(user, nil)
+		users.EXPECT().Recv().Return(nil, io.EOF)
+		mockUserClient.EXPECT().GetAll(ctx, gomock.Any()).Return(users, nil)
+		expectOverviews(&audit.UserOverview{
+			Email:   user.Email,
+			Roles:   "- system admin\n- tenant admin",
+			Tenants: "- the-tenant (admin)",
+		})
+		mockTenantClient.EXPECT().GetByName(ctx, wrapperspb.String(expectedTenant.Name)).Return(expectedTenant, nil)
+
+		uc := injectMocks(NewRestoreUserUseCase(conf, user.Email, DefaultRestoreBefore))
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(uc.out.(*bytes.Buffer).String()).To(ContainSubstring("with new id " + newId))
+
+		Expect(executed).To(HaveLen(3))
+		Expect(executed[0].Type).To(Equal(commandTypes.CreateUser.String()))
+		var roleBindings []*commanddata.CreateUserRoleBindingCommandData
+		for _, command := range executed[1:] {
+			Expect(command.Type).To(Equal(commandTypes.CreateUserRoleBinding.String()))
+			data := new(commanddata.CreateUserRoleBindingCommandData)
+			Expect(command.Data.UnmarshalTo(data)).To(Succeed())
+			Expect(data.UserId).To(Equal(newId))
+			roleBindings = append(roleBindings, data)
+		}
+		Expect(roleBindings[0].Scope).To(Equal("system"))
+		Expect(roleBindings[1].Scope).To(Equal("tenant"))
+		Expect(roleBindings[1].Resource.GetValue()).To(Equal(expectedTenant.Id))
+	})
+	It("refuses to restore a user which is not deleted", func() {
+		users := mdom.NewMockUser_GetAllClient(mockCtrl)
+		users.EXPECT().Recv().Return(&projections.User{Id: uuid.New().String(), Email: "jane.doe@monoskope.io"}, nil)
+		mockUserClient.EXPECT().GetAll(ctx, gomock.Any()).Return(users, nil)
+
+		uc := injectMocks(NewRestoreUserUseCase(conf, "jane.doe@monoskope.io", DefaultRestoreBefore))
+		err := uc.Run(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("user 'jane.doe@monoskope.io' is not deleted"))
+		Expect(executed).To(BeEmpty())
+	})
+	It("re-creates a deleted cluster and grants the tenants which had access again", func() {
+		cluster := &projections.Cluster{
+			Id:               uuid.New().String(),
+			Name:             expectedCluster.Name,
+			ApiServerAddress: "https://api.the-cluster.example.com",
+			CaCertBundle:     []byte("ca"),
+			Metadata:         deletedMetadata,
+		}
+		clusters := mdom.NewMockCluster_GetAllClient(mockCtrl)
+		clusters.EXPECT().Recv().Return(cluster, nil)
+		clusters.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterClient.EXPECT().GetAll(ctx, gomock.Any()).Return(clusters, nil)
+		expectBindingEvents(
+			&audit.HumanReadableEvent{
+				EventType: events.TenantClusterBindingCreated.String(),
+				Details:   "“admin@monoskope.io“ granted tenant “the-tenant“ access to cluster “the-cluster”",
+			},
+			&audit.HumanReadableEvent{
+				EventType: events.TenantClusterBindingCreated.String(),
+				Details:   "“admin@monoskope.io“ granted tenant “other-tenant“ access to cluster “the-cluster”",
+			},
+			&audit.HumanReadableEvent{
+				EventType: events.TenantClusterBindingDeleted.String(),
+				Details:   "“admin@monoskope.io“ revoked access to cluster “the-cluster“ for tenant “other-tenant“",
+			},
+		)
+		mockTenantClient.EXPECT().GetByName(ctx, wrapperspb.String(expectedTenant.Name)).Return(expectedTenant, nil)
+
+		uc := injectMocks(NewRestoreClusterUseCase(conf, cluster.Id, DefaultRestoreBefore))
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(uc.out.(*bytes.Buffer).String()).To(MatchRegexp(`(?m)^1\s+cluster access\s+tenant 'the-tenant' on cluster 'the-cluster'\s+restored\s*$`))
+
+		Expect(executed).To(HaveLen(2))
+		clusterData := new(commanddata.CreateCluster)
+		Expect(executed[0].Data.UnmarshalTo(clusterData)).To(Succeed())
+		Expect(clusterData.ApiServerAddress).To(Equal(cluster.ApiServerAddress))
+		bindingData := new(commanddata.CreateTenantClusterBindingCommandData)
+		Expect(executed[1].Data.UnmarshalTo(bindingData)).To(Succeed())
+		Expect(bindingData.TenantId).To(Equal(expectedTenant.Id))
+		Expect(bindingData.ClusterId).To(Equal(newId))
+	})
+	It("reports dependents which can not be reattached", func() {
+		tenant := &projections.Tenant{
+			Id:       uuid.New().String(),
+			Name:     expectedTenant.Name,
+			Prefix:   "tt",
+			Metadata: deletedMetadata,
+		}
+		tenants := mdom.NewMockTenant_GetAllClient(mockCtrl)
+		tenants.EXPECT().Recv().Return(tenant, nil)
+		tenants.EXPECT().Recv().Return(nil, io.EOF)
+		mockTenantClient.EXPECT().GetAll(ctx, gomock.Any()).Return(tenants, nil)
+		expectOverviews(&audit.UserOverview{
+			Email:   "jane.doe@monoskope.io",
+			Tenants: "- the-tenant (user)\n- other-tenant (admin)",
+		})
+		mockUserClient.EXPECT().GetByEmail(ctx, wrapperspb.String("jane.doe@monoskope.io")).Return(&projections.User{Id: uuid.New().String(), Email: "jane.doe@monoskope.io"}, nil)
+		expectBindingEvents(&audit.HumanReadableEvent{
+			EventType: events.TenantClusterBindingCreated.String(),
+			Details:   "“admin@monoskope.io“ granted tenant “the-tenant“ access to cluster “gone-cluster”",
+		})
+		mockClusterClient.EXPECT().GetByName(ctx, wrapperspb.String("gone-cluster")).Return(nil, io.ErrUnexpectedEOF)
+
+		uc := injectMocks(NewRestoreTenantUseCase(conf, tenant.Name, DefaultRestoreBefore))
+		err := uc.Run(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("failed reattaching 1 of 2 rolebindings and cluster access"))
+		// the tenant and the rolebinding of its member
+		Expect(executed).To(HaveLen(2))
+	})
+
+	Context("rolebinding", func() {
+		var user *projections.User
+
+		BeforeEach(func() {
+			user = &projections.User{
+				Id:    uuid.New().String(),
+				Email: "jane.doe@monoskope.io",
+				Metadata: &projections.LifecycleMetadata{
+					Created: timestamppb.New(created),
+				},
+			}
+			mockUserClient.EXPECT().GetByEmail(ctx, wrapperspb.String(user.Email)).Return(user, nil)
+		})
+
+		It("re-creates a deleted system rolebinding", func() {
+			expectBindingEvents(&audit.HumanReadableEvent{
+				Timestamp: timestamppb.New(deletedAt),
+				EventType: events.UserRoleBindingDeleted.String(),
+				Details:   "“admin@monoskope.io“ removed the role “admin“ for scope “system“ from user “jane.doe@monoskope.io“",
+			})
+
+			uc := injectMocks(NewRestoreRoleBindingUseCase(conf, user.Email, "admin", "system", "", DefaultRestoreBefore))
+			Expect(uc.Run(ctx)).To(Succeed())
+			Expect(uc.out.(*bytes.Buffer).String()).To(Equal("Restored rolebinding admin of jane.doe@monoskope.io with new id " + newId + ".\n"))
+
+			Expect(executed).To(HaveLen(1))
+			bindingData := new(commanddata.CreateUserRoleBindingCommandData)
+			Expect(executed[0].Data.UnmarshalTo(bindingData)).To(Succeed())
+			Expect(bindingData.UserId).To(Equal(user.Id))
+			Expect(bindingData.Role).To(Equal("admin"))
+			Expect(bindingData.Scope).To(Equal("system"))
+		})
+
+		It("re-creates a deleted tenant rolebinding on the tenant the user had it on before the deletion", func() {
+			otherTenant := &projections.Tenant{Id: uuid.New().String(), Name: "other-tenant"}
+			user.Roles = []*projections.UserRoleBinding{{Role: "user", Scope: "tenant", Resource: otherTenant.Id}}
+			expectBindingEvents(&audit.HumanReadableEvent{
+				Timestamp: timestamppb.New(deletedAt),
+				EventType: events.UserRoleBindingDeleted.String(),
+				Details:   "“admin@monoskope.io“ removed the role “user“ for scope “tenant“ from user “jane.doe@monoskope.io“",
+			})
+			expectOverviews(&audit.UserOverview{
+				Email:   user.Email,
+				Tenants: "- the-tenant (user)\n- other-tenant (user)",
+			})
+			mockTenantClient.EXPECT().GetByName(ctx, wrapperspb.String(expectedTenant.Name)).Return(expectedTenant, nil)
+			mockTenantClient.EXPECT().GetByName(ctx, wrapperspb.String(otherTenant.Name)).Return(otherTenant, nil)
+
+			uc := injectMocks(NewRestoreRoleBindingUseCase(conf, user.Email, "user", "tenant", "", DefaultRestoreBefore))
+			Expect(uc.Run(ctx)).To(Succeed())
+
+			Expect(executed).To(HaveLen(1))
+			bindingData := new(commanddata.CreateUserRoleBindingCommandData)
+			Expect(executed[0].Data.UnmarshalTo(bindingData)).To(Succeed())
+			Expect(bindingData.Resource.GetValue()).To(Equal(expectedTenant.Id))
+		})
+
+		It("asks for the tenant if the rolebinding was on one of several", func() {
+			expectBindingEvents(&audit.HumanReadableEvent{
+				Timestamp: timestamppb.New(deletedAt),
+				EventType: events.UserRoleBindingDeleted.String(),
+				Details:   "“admin@monoskope.io“ removed the role “user“ for scope “tenant“ from user “jane.doe@monoskope.io“",
+			})
+			expectOverviews(&audit.UserOverview{
+				Email:   user.Email,
+				Tenants: "- the-tenant (user)\n- other-tenant (user)",
+			})
+			mockTenantClient.EXPECT().GetByName(ctx, gomock.Any()).Return(&projections.Tenant{Id: uuid.New().String()}, nil).Times(2)
+
+			uc := injectMocks(NewRestoreRoleBindingUseCase(conf, user.Email, "user", "tenant", "", DefaultRestoreBefore))
+			err := uc.Run(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("choose with --tenant"))
+			Expect(executed).To(BeEmpty())
+		})
+
+		It("fails if the audit log shows no deletion of the rolebinding", func() {
+			expectBindingEvents(&audit.HumanReadableEvent{
+				Timestamp: timestamppb.New(deletedAt),
+				EventType: events.UserRoleBindingDeleted.String(),
+				Details:   "“admin@monoskope.io“ removed the role “admin“ for scope “system“ from user “john.doe@monoskope.io“",
+			})
+
+			uc := injectMocks(NewRestoreRoleBindingUseCase(conf, user.Email, "admin", "system", "", DefaultRestoreBefore))
+			err := uc.Run(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("no deleted rolebinding admin of jane.doe@monoskope.io in scope 'system' found"))
+			Expect(executed).To(BeEmpty())
+		})
+	})
+})
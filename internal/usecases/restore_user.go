@@ -0,0 +1,124 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	cmdData "github.com/finleap-connect/monoskope/pkg/api/domain/commanddata"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	cmd "github.com/finleap-connect/monoskope/pkg/domain/commands"
+	commandTypes "github.com/finleap-connect/monoskope/pkg/domain/constants/commands"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/scopes"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// NewRestoreUserUseCase returns the use-case re-creating the deleted user with the given email or id
+// and reattaching the rolebindings the user had the given duration before the deletion.
+func NewRestoreUserUseCase(config *config.Config, emailOrId string, before time.Duration) UseCase {
+	useCase := newRestoreUseCase(config, "user", emailOrId, before)
+	useCase.find = useCase.findUser
+	return useCase
+}
+
+// findDeletedUser returns the most recently deleted user with the email or id
+func (u *restoreUseCase) findDeletedUser(ctx context.Context) (*projections.User, error) {
+	stream, err := u.userClient.GetAll(ctx, &api.GetAllRequest{IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted *projections.User
+	for {
+		// Read next
+		user, err := stream.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		if user.Email != u.nameOrId && user.Id != u.nameOrId {
+			continue
+		}
+		if !isDeleted(user.Metadata) {
+			return nil, fmt.Errorf("user '%s' is not deleted", u.nameOrId)
+		}
+		if deleted == nil || user.Metadata.Deleted.AsTime().After(deleted.Metadata.Deleted.AsTime()) {
+			deleted = user
+		}
+	}
+	if deleted == nil {
+		return nil, fmt.Errorf("no deleted user '%s' found", u.nameOrId)
+	}
+	return deleted, nil
+}
+
+func (u *restoreUseCase) findUser(ctx context.Context) (*restoreTarget, error) {
+	user, err := u.findDeletedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &restoreTarget{
+		create: &restoreStep{
+			kind: "user",
+			name: fmt.Sprintf("user '%s'", user.Email),
+			command: cmd.NewCommandWithData(uuid.Nil, commandTypes.CreateUser, &cmdData.CreateUserCommandData{
+				Name:  user.Name,
+				Email: user.Email,
+			}),
+		},
+		id:      user.Id,
+		deleted: user.Metadata.Deleted.AsTime(),
+	}
+	target.reattach = func(ctx context.Context, id string) ([]*restoreStep, error) {
+		overviews, err := u.userOverviews(ctx, u.restorePoint(target))
+		if err != nil {
+			return nil, err
+		}
+
+		restored := &projections.User{Id: id, Name: user.Name, Email: user.Email}
+		var steps []*restoreStep
+		for _, overview := range overviews {
+			if overview.Email != user.Email {
+				continue
+			}
+			for _, role := range parseOverviewRoles(overview) {
+				if role.scope != string(scopes.Tenant) {
+					steps = append(steps, createRoleBindingStep(restored, role.role, role.scope, "", ""))
+					continue
+				}
+				tenant, err := u.tenantClient.GetByName(ctx, wrapperspb.String(role.tenant))
+				if err != nil {
+					steps = append(steps, failedStep(deleteKindRoleBinding, fmt.Sprintf("%s of %s on tenant '%s'", role.role, user.Email, role.tenant), err))
+					continue
+				}
+				steps = append(steps, createRoleBindingStep(restored, role.role, role.scope, tenant.Id, tenant.Name))
+			}
+		}
+		return steps, nil
+	}
+	return target, nil
+}
@@ -17,6 +17,7 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/finleap-connect/monoctl/internal/config"
 	"github.com/finleap-connect/monoctl/internal/grpc"
@@ -37,6 +38,8 @@ type updateClusterUseCase struct {
 	newName             string
 	newApiServerAddress string
 	newCaCertBundle     []byte
+	// discoverCA returns the CA bundle presented by the API server, nil if the bundle is not discovered
+	discoverCA func(ctx context.Context, apiServerAddress string) ([]byte, error)
 }
 
 func NewUpdateClusterUseCase(config *config.Config, name, newName, newApiServerAddress string, newCaCertBundle []byte) UseCase {
@@ -50,7 +53,21 @@ func NewUpdateClusterUseCase(config *config.Config, name, newName, newApiServerA
 	return useCase
 }
 
+// NewUpdateClusterDiscoverCAUseCase returns the use-case updating the cluster with the CA bundle discovered at the new
+// API server address, or the current one if the address is not changed.
+func NewUpdateClusterDiscoverCAUseCase(config *config.Config, name, newName, newApiServerAddress string, discoverCA func(ctx context.Context, apiServerAddress string) ([]byte, error)) UseCase {
+	useCase := NewUpdateClusterUseCase(config, name, newName, newApiServerAddress, nil).(*updateClusterUseCase)
+	useCase.discoverCA = discoverCA
+	return useCase
+}
+
 func (u *updateClusterUseCase) Run(ctx context.Context) error {
+	if u.newCaCertBundle != nil {
+		if err := validateCABundle(u.newCaCertBundle, os.Stderr); err != nil {
+			return err
+		}
+	}
+
 	s := spinner.NewSpinner()
 	defer s.Stop()
 
@@ -66,6 +83,22 @@ func (u *updateClusterUseCase) Run(ctx context.Context) error {
 		return err
 	}
 
+	if u.discoverCA != nil {
+		// Discovering may ask for confirmation
+		s.Stop()
+
+		apiServerAddress := cluster.ApiServerAddress
+		if u.newApiServerAddress != "" {
+			apiServerAddress = u.newApiServerAddress
+		}
+		if u.newCaCertBundle, err = u.discoverCA(ctx, apiServerAddress); err != nil {
+			return err
+		}
+		if err := validateCABundle(u.newCaCertBundle, os.Stderr); err != nil {
+			return err
+		}
+	}
+
 	commandData := new(cmdData.UpdateCluster)
 	commandData.CaCertBundle = u.newCaCertBundle
 	if u.newName != "" {
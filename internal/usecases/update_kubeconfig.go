@@ -15,21 +15,25 @@
 package usecases
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"io"
-	"strings"
+	"os"
+	"reflect"
+	"sort"
 
 	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/diff"
 	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
 	"github.com/finleap-connect/monoctl/internal/k8s"
 	"github.com/finleap-connect/monoctl/internal/spinner"
 	api "github.com/finleap-connect/monoskope/pkg/api/domain"
 	projections "github.com/finleap-connect/monoskope/pkg/api/domain/projections"
-	mk8s "github.com/finleap-connect/monoskope/pkg/k8s"
 	ggrpc "google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"k8s.io/client-go/tools/clientcmd"
 	kapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
@@ -41,17 +45,46 @@ type UpdateKubeconfigUseCase struct {
 	configManager       *config.ClientConfigManager
 	userClient          api.UserClient
 	clusterAccessClient api.ClusterAccessClient
+	tenantClient        api.TenantClient
 	kubeConfig          *k8s.KubeConfig
 	kubeConfigPath      string
 	overwrite           bool
+	dryRun              bool
+	selection           *config.KubeconfigSelection
+	out                 io.Writer
+	diff                string
+	changes             *kubeconfigChanges
 }
 
-func NewUpdateKubeconfigUseCase(configManager *config.ClientConfigManager, kubeConfigPath string, overwrite bool) UseCase {
+// kubeconfigEntry is a monoctl managed context together with the cluster and user it refers to
+type kubeconfigEntry struct {
+	context  *kapi.Context
+	cluster  *kapi.Cluster
+	authInfo *kapi.AuthInfo
+}
+
+// kubeconfigChanges summarizes how the monoctl managed contexts changed
+type kubeconfigChanges struct {
+	added   []string
+	removed []string
+	updated []string
+	// renamed maps the old names of contexts to the new ones
+	renamed map[string]string
+	// lostClusters maps removed contexts to the cluster which is not accessible anymore
+	lostClusters map[string]string
+}
+
+// NewUpdateKubeconfigUseCase returns the use-case updating the kubeconfig.
+// If selection is nil the selection remembered in the monoconfig is used, an empty selection resets it.
+func NewUpdateKubeconfigUseCase(configManager *config.ClientConfigManager, kubeConfigPath string, overwrite, dryRun bool, selection *config.KubeconfigSelection) UseCase {
 	useCase := &UpdateKubeconfigUseCase{
 		useCaseBase:    NewUseCaseBase("create-kubeconfig", configManager.GetConfig()),
 		configManager:  configManager,
 		kubeConfigPath: kubeConfigPath,
 		overwrite:      overwrite,
+		dryRun:         dryRun,
+		selection:      selection,
+		out:            os.Stdout,
 	}
 	return useCase
 }
@@ -69,6 +102,7 @@ func (u *UpdateKubeconfigUseCase) init(ctx context.Context) error {
 	u.conn = conn
 	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
 	u.userClient = api.NewUserClient(u.conn)
+	u.tenantClient = api.NewTenantClient(u.conn)
 
 	u.kubeConfig = k8s.NewKubeConfig()
 	u.setInitialized()
@@ -76,35 +110,8 @@ func (u *UpdateKubeconfigUseCase) init(ctx context.Context) error {
 	return nil
 }
 
-func (u *UpdateKubeconfigUseCase) getNaming(m8ClusterName string, clusterRole string) (clusterName, contextName, nsName, authInfoName string, err error) {
-	if len(m8ClusterName) < 3 {
-		err = errors.New("clustername is too short")
-		return
-	}
-	if len(clusterRole) < 3 {
-		err = errors.New("clusterRole is too short")
-		return
-	}
-
-	nsName, err = mk8s.GetK8sName(strings.Replace(u.config.AuthInformation.Username, " ", "-", -1))
-	if err != nil {
-		return
-	}
-
-	clusterName, err = mk8s.GetK8sName(m8ClusterName)
-	if err != nil {
-		return
-	}
-
-	authInfoName = fmt.Sprintf("%s-%s-%s", clusterName, nsName, clusterRole)
-	contextName = fmt.Sprintf("%s-%s", clusterName, clusterRole)
-
-	u.log.Info("Naming configured for cluster.", "cluster", clusterName, "context", contextName, "ns", nsName, "authinfo", authInfoName)
-
-	return
-}
-
-// setContext sets the context the given on kubeconfig
+// setContext sets the context the given on kubeconfig.
+// The namespace of an existing context is only updated if it has not been changed by the user.
 func (u *UpdateKubeconfigUseCase) setContext(kubeConfig *kapi.Config, clusterName, contextName, nsName, authInfoName string) {
 	var ok bool
 	var kubeContext *kapi.Context
@@ -112,9 +119,12 @@ func (u *UpdateKubeconfigUseCase) setContext(kubeConfig *kapi.Config, clusterNam
 		kubeContext = kapi.NewContext()
 		kubeConfig.Contexts[contextName] = kubeContext
 	}
-	kubeContext.Namespace = nsName
+	if managed, isManaged := k8s.GetManaged(kubeContext.Extensions); !ok || kubeContext.Namespace == "" || (isManaged && kubeContext.Namespace == managed.Namespace) {
+		kubeContext.Namespace = nsName
+	}
 	kubeContext.Cluster = clusterName
 	kubeContext.AuthInfo = authInfoName
+	kubeContext.Extensions = k8s.SetManaged(kubeContext.Extensions, &k8s.Managed{Namespace: nsName})
 
 	u.log.Info("Context created/updated.", "context", contextName)
 }
@@ -131,6 +141,9 @@ func (u *UpdateKubeconfigUseCase) setCluster(kubeConfig *kapi.Config, m8Cluster
 	cluster.CertificateAuthorityData = m8Cluster.CaCertBundle
 	cluster.CertificateAuthority = "" // clear other authority data which clashes
 	cluster.Server = m8Cluster.ApiServerAddress
+	cluster.Extensions = k8s.SetManaged(cluster.Extensions, &k8s.Managed{})
+	// Passed by kubectl to monoctl as credential plugin
+	cluster.Extensions = k8s.SetExecClusterConfig(cluster.Extensions, &k8s.ExecClusterConfig{ClusterId: m8Cluster.Id})
 
 	u.log.Info("Cluster created/updated.", "cluster", clusterName)
 }
@@ -143,112 +156,544 @@ func (u *UpdateKubeconfigUseCase) setAuthInfo(kubeConfig *kapi.Config, authInfoN
 		kubeAuthInfo = kapi.NewAuthInfo()
 		kubeConfig.AuthInfos[authInfoName] = kubeAuthInfo
 	}
-	kubeAuthInfo.Exec = &kapi.ExecConfig{
-		APIVersion:  "client.authentication.k8s.io/v1beta1",
-		InstallHint: "Monoskope's commandline tool `monoctl` is required to authenticate to the current cluster.",
-		Command:     monoctlCmd,
-		Args: []string{
-			"get", "cluster-credentials", clusterId, string(clusterRole),
-		},
-		Env: make([]kapi.ExecEnvVar, 0),
+	if kubeAuthInfo.Exec == nil {
+		kubeAuthInfo.Exec = &kapi.ExecConfig{
+			Env: make([]kapi.ExecEnvVar, 0),
+		}
+	}
+	// A custom path to monoctl set by the user is kept
+	if kubeAuthInfo.Exec.Command == "" {
+		kubeAuthInfo.Exec.Command = monoctlCmd
 	}
+	kubeAuthInfo.Exec.APIVersion = u.config.GetExecCredentialAPIVersion()
+	kubeAuthInfo.Exec.InstallHint = "Monoskope's commandline tool `monoctl` is required to authenticate to the current cluster."
+	kubeAuthInfo.Exec.InteractiveMode = kapi.IfAvailableExecInteractiveMode
+	kubeAuthInfo.Exec.ProvideClusterInfo = true
+	kubeAuthInfo.Exec.Args = []string{
+		"get", "cluster-credentials", clusterId, string(clusterRole),
+	}
+	kubeAuthInfo.Extensions = k8s.SetManaged(kubeAuthInfo.Extensions, &k8s.Managed{})
 	u.log.Info("AuthInfo created/updated.", "authinfo", authInfoName)
 }
 
+// isManagedAuthInfo checks if the auth info is managed by monoctl.
+// Entries written by earlier versions of monoctl are recognized by the command of their exec config.
+func isManagedAuthInfo(authInfo *kapi.AuthInfo) bool {
+	return k8s.IsManaged(authInfo.Extensions) || (authInfo.Exec != nil && authInfo.Exec.Command == monoctlCmd)
+}
+
+// isManagedCluster checks if the cluster is managed by monoctl
+func isManagedCluster(kubeConfig *kapi.Config, clusterName string) bool {
+	if cluster, ok := kubeConfig.Clusters[clusterName]; ok && k8s.IsManaged(cluster.Extensions) {
+		return true
+	}
+	// Clusters written by earlier versions of monoctl are referenced by managed contexts
+	for _, kctx := range kubeConfig.Contexts {
+		if kctx.Cluster != clusterName {
+			continue
+		}
+		if authInfo, ok := kubeConfig.AuthInfos[kctx.AuthInfo]; ok && isManagedAuthInfo(authInfo) {
+			return true
+		}
+	}
+	return false
+}
+
 func (u *UpdateKubeconfigUseCase) run(ctx context.Context) error {
 	var err error
 
+	// A selection given by the user replaces the remembered one
+	if u.selection != nil {
+		if err := u.selection.Validate(); err != nil {
+			return err
+		}
+		u.config.KubeconfigSelection = u.selection
+		if u.selection.IsEmpty() {
+			u.config.KubeconfigSelection = nil
+		}
+	}
+
 	// Load kubeconfig of current user
 	var kubeConfig *kapi.Config
 	u.kubeConfig.SetPath(u.kubeConfigPath) // overwrite path from m8Config if new one is specified by user
 	if len(u.kubeConfig.ConfigPath) == 0 {
 		u.kubeConfig.SetPath(u.config.KubeConfigPath)
 	}
+	if u.config.KubeconfigBackupRetention != 0 {
+		u.kubeConfig.BackupRetention = u.config.KubeconfigBackupRetention
+	}
 	if kubeConfig, err = u.kubeConfig.LoadConfig(); err != nil {
 		return err
 	}
+	// A kubeconfig which does not exist yet is empty before the update
+	var before string
+	if _, err := os.Stat(u.kubeConfig.ConfigPath); err == nil {
+		if before, err = serializeKubeconfig(kubeConfig); err != nil {
+			return err
+		}
+	}
 
-	// Optionally clear config
-	if u.overwrite {
-		kubeConfig = kapi.NewConfig()
+	if err := k8s.ValidateExecCredentialAPIVersion(u.config.GetExecCredentialAPIVersion()); err != nil {
+		return err
+	}
+
+	namer, err := newKubeconfigNamer(u.config.GetKubeconfigNaming(), u.config.AuthInformation.Username, u.clusterAccessClient, u.tenantClient)
+	if err != nil {
+		return err
+	}
+
+	// Get cluster information from control plane
+	clusterAccesses, err := u.getClusterAccesses(ctx)
+	if err != nil {
+		return err
+	}
+	selector, err := u.newSelector(ctx, clusterAccesses)
+	if err != nil {
+		return err
 	}
 
-	// Find m8 auth infos
-	var m8AuthInfos []string
-	var m8Contexts []string
-	var m8Clusters []string
-	for authInfoName, authInfo := range kubeConfig.AuthInfos {
-		if authInfo.Exec == nil || authInfo.Exec.Command != monoctlCmd {
+	// Find the m8 entries managed by this run, entries outside of the selection are left untouched
+	oldEntries := make(map[string]*kubeconfigEntry)
+	// oldContexts maps the cluster roles of managed contexts to their names
+	oldContexts := make(map[string]string)
+	m8AuthInfos := make(map[string]bool)
+	m8Clusters := make(map[string]bool)
+	for contextName, kctx := range kubeConfig.Contexts {
+		authInfo, ok := kubeConfig.AuthInfos[kctx.AuthInfo]
+		if !ok || !isManagedAuthInfo(authInfo) || !selector.isSelectedEntry(kctx, authInfo) {
 			continue
 		}
-		m8AuthInfos = append(m8AuthInfos, authInfoName)
+		oldEntries[contextName] = &kubeconfigEntry{context: kctx.DeepCopy(), authInfo: authInfo.DeepCopy()}
+		if cluster, ok := kubeConfig.Clusters[kctx.Cluster]; ok {
+			oldEntries[contextName].cluster = cluster.DeepCopy()
+			m8Clusters[kctx.Cluster] = true
+		}
+		m8AuthInfos[kctx.AuthInfo] = true
+		if clusterId, role, ok := parseCredentialArgs(authInfo.Exec); ok {
+			oldContexts[credentialKey(clusterId, role)] = contextName
+		}
+	}
+	// Without selection also auth infos without context are cleaned up
+	if selector.selection == nil {
+		for authInfoName, authInfo := range kubeConfig.AuthInfos {
+			if isManagedAuthInfo(authInfo) {
+				m8AuthInfos[authInfoName] = true
+			}
+		}
+	}
 
-		for contextName, kctx := range kubeConfig.Contexts {
-			if kctx.AuthInfo != authInfoName {
+	// Optionally clear config
+	if u.overwrite {
+		kubeConfig = kapi.NewConfig()
+	}
+
+	// newContexts maps the written contexts to the cluster role they grant access to
+	newContexts := make(map[string]string)
+	for _, clusterAccess := range clusterAccesses {
+		for _, clusterRole := range clusterAccess.ClusterRoles {
+			if !selector.isSelected(clusterAccess.Cluster.Id, clusterAccess.Cluster.Name, clusterRole.Role) {
 				continue
 			}
-			m8Contexts = append(m8Contexts, contextName)
 
-			for clusterName := range kubeConfig.Clusters {
-				if clusterName != kctx.Cluster {
-					continue
+			// Get naming
+			names, err := namer.names(ctx, clusterAccess.Cluster.Id, clusterAccess.Cluster.Name, clusterRole.Role)
+			if err != nil {
+				return err
+			}
+			u.log.Info("Naming configured for cluster.", "cluster", names.cluster, "context", names.context, "ns", names.namespace, "authinfo", names.authInfo)
+			key := credentialKey(clusterAccess.Cluster.Id, clusterRole.Role)
+
+			// Entries which are not managed by this run must not be overwritten
+			if _, exists := kubeConfig.Contexts[names.context]; exists && oldEntries[names.context] == nil {
+				if _, written := newContexts[names.context]; !written {
+					return fmt.Errorf("context name '%s' collides with an existing context not managed by this update", names.context)
 				}
-				m8Clusters = append(m8Clusters, clusterName)
 			}
+			if _, exists := kubeConfig.AuthInfos[names.authInfo]; exists && !m8AuthInfos[names.authInfo] && !isAuthInfoWritten(kubeConfig, newContexts, names.authInfo) {
+				return fmt.Errorf("user name '%s' collides with an existing user not managed by this update", names.authInfo)
+			}
+			if _, exists := kubeConfig.Clusters[names.cluster]; exists && !isManagedCluster(kubeConfig, names.cluster) {
+				return fmt.Errorf("cluster name '%s' collides with an existing cluster not managed by monoctl", names.cluster)
+			}
+
+			// Move renamed entries to keep the customizations of the user
+			if oldName, ok := oldContexts[key]; ok && oldName != names.context {
+				u.renameEntry(kubeConfig, newContexts, oldName, names)
+			}
+
+			// Set cluster on kubeconfig
+			u.setCluster(kubeConfig, clusterAccess.Cluster, names.cluster)
+
+			// Set context on kubeconfig
+			u.setContext(kubeConfig, names.cluster, names.context, names.namespace, names.authInfo)
+
+			// Set credentials on kubeconfig
+			u.setAuthInfo(kubeConfig, names.authInfo, clusterAccess.Cluster.Id, clusterRole.Role)
+
+			newContexts[names.context] = key
 		}
 	}
 
-	// Delete all old stuff
-	for _, name := range m8AuthInfos {
-		delete(kubeConfig.AuthInfos, name)
+	// Delete entries which are not accessible anymore
+	for contextName := range oldEntries {
+		if _, written := newContexts[contextName]; !written {
+			delete(kubeConfig.Contexts, contextName)
+		}
 	}
-	for _, name := range m8Contexts {
-		delete(kubeConfig.Contexts, name)
+	for authInfoName := range m8AuthInfos {
+		if !isAuthInfoReferenced(kubeConfig, authInfoName) {
+			delete(kubeConfig.AuthInfos, authInfoName)
+		}
 	}
-	for _, name := range m8Clusters {
-		delete(kubeConfig.Clusters, name)
+	for clusterName := range m8Clusters {
+		if !isClusterReferenced(kubeConfig, clusterName) {
+			delete(kubeConfig.Clusters, clusterName)
+		}
 	}
 
-	// Get cluster information from control plane
-	clusterAccesses, err := u.clusterAccessClient.GetClusterAccessV2(ctx, &emptypb.Empty{})
-	if err != nil {
+	u.changes = diffEntries(oldEntries, newContexts, kubeConfig)
+
+	// Keep the current context if it has been renamed
+	if newName, ok := u.changes.renamed[kubeConfig.CurrentContext]; ok {
+		kubeConfig.CurrentContext = newName
+	}
+
+	if u.dryRun {
+		after, err := serializeKubeconfig(kubeConfig)
+		if err != nil {
+			return err
+		}
+		u.diff = diff.Unified(u.kubeConfig.ConfigPath, u.kubeConfig.ConfigPath, before, after, diff.DefaultContextLines)
+		return nil
+	}
+
+	if err := u.kubeConfig.StoreConfig(kubeConfig); err != nil {
 		return err
 	}
+	u.config.KubeConfigPath = u.kubeConfig.ConfigPath
+	return u.configManager.SaveConfig()
+}
+
+// getClusterAccesses returns the clusters and roles the user has access to
+func (u *UpdateKubeconfigUseCase) getClusterAccesses(ctx context.Context) ([]*projections.ClusterAccessV2, error) {
+	return getClusterAccesses(ctx, u.clusterAccessClient)
+}
+
+// getClusterAccesses returns the clusters and roles the user has access to
+func getClusterAccesses(ctx context.Context, clusterAccessClient api.ClusterAccessClient) ([]*projections.ClusterAccessV2, error) {
+	clusterAccessStream, err := clusterAccessClient.GetClusterAccessV2(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
 
+	var clusterAccesses []*projections.ClusterAccessV2
 	for {
 		// Read next
-		clusterAccess, err := clusterAccesses.Recv()
+		clusterAccess, err := clusterAccessStream.Recv()
 		// End of stream
 		if err == io.EOF {
 			break
 		}
 		if err != nil { // Some other error
-			return err
+			return nil, err
 		}
+		clusterAccesses = append(clusterAccesses, clusterAccess)
+	}
+	return clusterAccesses, nil
+}
 
-		for _, clusterRole := range clusterAccess.ClusterRoles {
-			// Get naming
-			clusterName, contextName, nsName, authInfoName, err := u.getNaming(clusterAccess.Cluster.Name, clusterRole.Role)
+// newSelector returns the selector of the clusters and roles managed by this run
+func (u *UpdateKubeconfigUseCase) newSelector(ctx context.Context, clusterAccesses []*projections.ClusterAccessV2) (*kubeconfigSelector, error) {
+	selector := &kubeconfigSelector{
+		selection:    u.config.KubeconfigSelection,
+		clusterNames: make(map[string]string),
+	}
+	for _, clusterAccess := range clusterAccesses {
+		selector.clusterNames[clusterAccess.Cluster.Id] = clusterAccess.Cluster.Name
+	}
+	if selector.selection == nil || len(selector.selection.Tenants) == 0 {
+		return selector, nil
+	}
+
+	// Resolve the clusters of the selected tenants
+	selector.tenantClusterIds = make(map[string]bool)
+	tenantStream, err := u.tenantClient.GetAll(ctx, &api.GetAllRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// Read next
+		tenant, err := tenantStream.Recv()
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+		if !selector.selection.MatchesTenant(tenant.Name) {
+			continue
+		}
+
+		bindingStream, err := u.clusterAccessClient.GetTenantClusterMappingsByTenantId(ctx, wrapperspb.String(tenant.Id))
+		if err != nil {
+			return nil, err
+		}
+		for {
+			binding, err := bindingStream.Recv()
+			if err == io.EOF {
+				break
+			}
 			if err != nil {
-				return err
+				return nil, err
 			}
+			selector.tenantClusterIds[binding.ClusterId] = true
+		}
+	}
 
-			// Set cluster on kubeconfig
-			u.setCluster(kubeConfig, clusterAccess.Cluster, clusterName)
+	return selector, nil
+}
 
-			// Set context on kubeconfig
-			u.setContext(kubeConfig, clusterName, contextName, nsName, authInfoName)
+// kubeconfigSelector decides which clusters and roles are managed by a run of update kubeconfig
+type kubeconfigSelector struct {
+	selection *config.KubeconfigSelection
+	// clusterNames maps the ids of accessible clusters to their names
+	clusterNames map[string]string
+	// tenantClusterIds contains the ids of the clusters of the selected tenants, nil if not filtered by tenant
+	tenantClusterIds map[string]bool
+}
 
-			// Set credentials on kubeconfig
-			u.setAuthInfo(kubeConfig, authInfoName, clusterAccess.Cluster.Id, clusterRole.Role)
+// isSelected checks if the role on the cluster is part of the selection
+func (s *kubeconfigSelector) isSelected(clusterId, clusterName, role string) bool {
+	if s.selection == nil {
+		return true
+	}
+	if !s.selection.MatchesCluster(clusterName) || !s.selection.MatchesRole(role) || s.selection.IsExcluded(clusterName, role) {
+		return false
+	}
+	// Tenants of clusters which are not accessible anymore are unknown, so they are not filtered by tenant
+	if _, accessible := s.clusterNames[clusterId]; accessible && s.tenantClusterIds != nil {
+		return s.tenantClusterIds[clusterId]
+	}
+	return true
+}
+
+// isSelectedEntry checks if the monoctl managed context is part of the selection
+func (s *kubeconfigSelector) isSelectedEntry(kctx *kapi.Context, authInfo *kapi.AuthInfo) bool {
+	if s.selection == nil {
+		return true
+	}
+	clusterId, role, ok := parseCredentialArgs(authInfo.Exec)
+	if !ok {
+		return false
+	}
+	// The m8 name of clusters which are not accessible anymore is unknown, the name in the kubeconfig is used instead
+	clusterName, ok := s.clusterNames[clusterId]
+	if !ok {
+		clusterName = kctx.Cluster
+	}
+	return s.isSelected(clusterId, clusterName, role)
+}
+
+// parseCredentialArgs returns the cluster id and role of the credentials requested by a monoctl managed auth info
+func parseCredentialArgs(exec *kapi.ExecConfig) (clusterId, role string, ok bool) {
+	if exec == nil || len(exec.Args) < 4 || exec.Args[0] != "get" || exec.Args[1] != "cluster-credentials" {
+		return "", "", false
+	}
+	return exec.Args[2], exec.Args[3], true
+}
+
+// credentialKey identifies the cluster role a monoctl managed auth info requests credentials for
+func credentialKey(clusterId, role string) string {
+	return clusterId + "/" + role
+}
+
+// isAuthInfoWritten checks if the auth info has already been written for one of the new contexts
+func isAuthInfoWritten(kubeConfig *kapi.Config, newContexts map[string]string, authInfoName string) bool {
+	for contextName := range newContexts {
+		if kubeConfig.Contexts[contextName].AuthInfo == authInfoName {
+			return true
 		}
 	}
+	return false
+}
 
-	if err := u.kubeConfig.StoreConfig(kubeConfig); err != nil {
-		return err
+// renameEntry moves the context and auth info of a cluster role to their new names
+func (u *UpdateKubeconfigUseCase) renameEntry(kubeConfig *kapi.Config, newContexts map[string]string, oldName string, names *kubeconfigNames) {
+	kctx, ok := kubeConfig.Contexts[oldName]
+	if !ok {
+		return
+	}
+	if _, written := newContexts[oldName]; written {
+		return
+	}
+	if _, exists := kubeConfig.Contexts[names.context]; !exists {
+		kubeConfig.Contexts[names.context] = kctx
+		delete(kubeConfig.Contexts, oldName)
+	}
+	if kctx.AuthInfo == names.authInfo {
+		return
+	}
+	if authInfo, ok := kubeConfig.AuthInfos[kctx.AuthInfo]; ok {
+		if _, exists := kubeConfig.AuthInfos[names.authInfo]; !exists {
+			kubeConfig.AuthInfos[names.authInfo] = authInfo
+			delete(kubeConfig.AuthInfos, kctx.AuthInfo)
+		}
+	}
+	u.log.Info("Context renamed.", "context", oldName, "newContext", names.context)
+}
+
+// isAuthInfoReferenced checks if any context of the kubeconfig refers to the auth info
+func isAuthInfoReferenced(kubeConfig *kapi.Config, authInfoName string) bool {
+	for _, kctx := range kubeConfig.Contexts {
+		if kctx.AuthInfo == authInfoName {
+			return true
+		}
+	}
+	return false
+}
+
+// isClusterReferenced checks if any context of the kubeconfig refers to the cluster
+func isClusterReferenced(kubeConfig *kapi.Config, clusterName string) bool {
+	for _, kctx := range kubeConfig.Contexts {
+		if kctx.Cluster == clusterName {
+			return true
+		}
+	}
+	return false
+}
+
+// serializeKubeconfig returns the kubeconfig as YAML
+func serializeKubeconfig(kubeConfig *kapi.Config) (string, error) {
+	data, err := clientcmd.Write(*kubeConfig)
+	if err != nil {
+		return "", err
+	}
+	// Round trip to apply the defaults of loading, which otherwise show up as changes
+	normalized, err := clientcmd.Load(data)
+	if err != nil {
+		return "", err
+	}
+	data, err = clientcmd.Write(*normalized)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// diffEntries compares the previously managed contexts with the ones written now
+func diffEntries(oldEntries map[string]*kubeconfigEntry, newContexts map[string]string, kubeConfig *kapi.Config) *kubeconfigChanges {
+	changes := &kubeconfigChanges{
+		renamed:      make(map[string]string),
+		lostClusters: make(map[string]string),
+	}
+
+	// Old contexts which are not written anymore by their cluster role
+	oldContexts := make(map[string]string)
+	for contextName, oldEntry := range oldEntries {
+		if _, ok := newContexts[contextName]; ok {
+			continue
+		}
+		if clusterId, role, ok := parseCredentialArgs(oldEntry.authInfo.Exec); ok {
+			oldContexts[credentialKey(clusterId, role)] = contextName
+		}
+	}
+
+	newClusters := make(map[string]bool)
+	for contextName, key := range newContexts {
+		newClusters[kubeConfig.Contexts[contextName].Cluster] = true
+
+		oldEntry, ok := oldEntries[contextName]
+		if !ok {
+			if oldName, renamed := oldContexts[key]; renamed {
+				changes.renamed[oldName] = contextName
+			} else {
+				changes.added = append(changes.added, contextName)
+			}
+			continue
+		}
+		newEntry := &kubeconfigEntry{
+			context:  kubeConfig.Contexts[contextName],
+			cluster:  kubeConfig.Clusters[kubeConfig.Contexts[contextName].Cluster],
+			authInfo: kubeConfig.AuthInfos[kubeConfig.Contexts[contextName].AuthInfo],
+		}
+		if !oldEntry.equals(newEntry) {
+			changes.updated = append(changes.updated, contextName)
+		}
+	}
+	for contextName, oldEntry := range oldEntries {
+		if _, ok := newContexts[contextName]; ok {
+			continue
+		}
+		if _, ok := changes.renamed[contextName]; ok {
+			continue
+		}
+		changes.removed = append(changes.removed, contextName)
+		if !newClusters[oldEntry.context.Cluster] {
+			changes.lostClusters[contextName] = oldEntry.context.Cluster
+		}
+	}
+
+	sort.Strings(changes.added)
+	sort.Strings(changes.removed)
+	sort.Strings(changes.updated)
+
+	return changes
+}
+
+// equals compares the fields of the entries monoctl manages
+func (e *kubeconfigEntry) equals(other *kubeconfigEntry) bool {
+	if e.context.Cluster != other.context.Cluster ||
+		e.context.AuthInfo != other.context.AuthInfo ||
+		e.context.Namespace != other.context.Namespace {
+		return false
+	}
+	if (e.cluster == nil) != (other.cluster == nil) {
+		return false
+	}
+	if e.cluster != nil && (e.cluster.Server != other.cluster.Server ||
+		!bytes.Equal(e.cluster.CertificateAuthorityData, other.cluster.CertificateAuthorityData)) {
+		return false
+	}
+	if (e.authInfo.Exec == nil) != (other.authInfo.Exec == nil) {
+		return false
+	}
+	return e.authInfo.Exec == nil || (e.authInfo.Exec.APIVersion == other.authInfo.Exec.APIVersion &&
+		e.authInfo.Exec.Command == other.authInfo.Exec.Command &&
+		e.authInfo.Exec.ProvideClusterInfo == other.authInfo.Exec.ProvideClusterInfo &&
+		reflect.DeepEqual(e.authInfo.Exec.Args, other.authInfo.Exec.Args))
+}
+
+// printSummary prints the changes of the monoctl managed contexts
+func (u *UpdateKubeconfigUseCase) printSummary() {
+	if u.changes == nil {
+		return
+	}
+	if len(u.changes.added)+len(u.changes.removed)+len(u.changes.updated)+len(u.changes.renamed) == 0 {
+		fmt.Fprintln(u.out, "No contexts changed.")
+		return
+	}
+	for _, contextName := range u.changes.added {
+		fmt.Fprintf(u.out, "Context '%s' added.\n", contextName)
+	}
+	for _, contextName := range u.changes.updated {
+		fmt.Fprintf(u.out, "Context '%s' updated.\n", contextName)
+	}
+	oldNames := make([]string, 0, len(u.changes.renamed))
+	for oldName := range u.changes.renamed {
+		oldNames = append(oldNames, oldName)
+	}
+	sort.Strings(oldNames)
+	for _, oldName := range oldNames {
+		fmt.Fprintf(u.out, "Context '%s' renamed to '%s'.\n", oldName, u.changes.renamed[oldName])
+	}
+	for _, contextName := range u.changes.removed {
+		if cluster, ok := u.changes.lostClusters[contextName]; ok {
+			fmt.Fprintf(u.out, "Context '%s' removed, lost access to cluster '%s'.\n", contextName, cluster)
+		} else {
+			fmt.Fprintf(u.out, "Context '%s' removed.\n", contextName)
+		}
 	}
-	u.config.KubeConfigPath = u.kubeConfig.ConfigPath
-	return u.configManager.SaveConfig()
 }
 
 func (u *UpdateKubeconfigUseCase) Run(ctx context.Context) error {
@@ -269,9 +714,20 @@ func (u *UpdateKubeconfigUseCase) Run(ctx context.Context) error {
 	}
 	s.Stop()
 
-	fmt.Println("Your kubeconfig has been updated.")
-	fmt.Println("Use `kubectl config get-contexts` to see available contexts.")
-	fmt.Println("Use `kubectl config use-context <CONTEXTNAME>` to switch between clusters.")
+	if u.dryRun {
+		if u.diff == "" {
+			fmt.Fprintln(u.out, "No changes.")
+		} else {
+			fmt.Fprint(u.out, u.diff)
+		}
+		u.printSummary()
+		return nil
+	}
+
+	u.printSummary()
+	fmt.Fprintln(u.out, "Your kubeconfig has been updated.")
+	fmt.Fprintln(u.out, "Use `kubectl config get-contexts` to see available contexts.")
+	fmt.Fprintln(u.out, "Use `kubectl config use-context <CONTEXTNAME>` to switch between clusters.")
 
 	return nil
 }
@@ -0,0 +1,200 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	mk8s "github.com/finleap-connect/monoskope/pkg/k8s"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// kubeconfigNames are the names of the kubeconfig entries generated for a role on a cluster
+type kubeconfigNames struct {
+	cluster   string
+	context   string
+	authInfo  string
+	namespace string
+}
+
+// kubeconfigNameData is the data the naming templates are rendered with
+type kubeconfigNameData struct {
+	// Cluster is the name of the cluster entry in the kubeconfig
+	Cluster string
+	// Role is the cluster role
+	Role string
+	// User is the name of the current user
+	User string
+
+	tenant func() (string, error)
+}
+
+// Tenant returns the name of the tenant the cluster belongs to
+func (d *kubeconfigNameData) Tenant() (string, error) {
+	return d.tenant()
+}
+
+// kubeconfigNamer renders the names of the generated kubeconfig entries from the configured templates
+type kubeconfigNamer struct {
+	contextTemplate   *template.Template
+	authInfoTemplate  *template.Template
+	namespaceTemplate *template.Template
+	user              string
+	// owners maps the rendered context and auth info names to the cluster role they were rendered for
+	contextOwners  map[string]string
+	authInfoOwners map[string]string
+	// tenants caches the tenant names per cluster id
+	tenants             map[string]string
+	clusterAccessClient api.ClusterAccessClient
+	tenantClient        api.TenantClient
+}
+
+// newKubeconfigNamer parses the naming templates
+func newKubeconfigNamer(naming *config.KubeconfigNaming, username string, clusterAccessClient api.ClusterAccessClient, tenantClient api.TenantClient) (*kubeconfigNamer, error) {
+	user, err := mk8s.GetK8sName(strings.Replace(username, " ", "-", -1))
+	if err != nil {
+		return nil, err
+	}
+
+	namer := &kubeconfigNamer{
+		user:                user,
+		contextOwners:       make(map[string]string),
+		authInfoOwners:      make(map[string]string),
+		tenants:             make(map[string]string),
+		clusterAccessClient: clusterAccessClient,
+		tenantClient:        tenantClient,
+	}
+	if namer.contextTemplate, err = parseNamingTemplate("context", naming.Context); err != nil {
+		return nil, err
+	}
+	if namer.authInfoTemplate, err = parseNamingTemplate("authInfo", naming.AuthInfo); err != nil {
+		return nil, err
+	}
+	if namer.namespaceTemplate, err = parseNamingTemplate("namespace", naming.Namespace); err != nil {
+		return nil, err
+	}
+	return namer, nil
+}
+
+func parseNamingTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s naming template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// names renders the names of the kubeconfig entries for the role on the cluster and checks them for collisions
+func (n *kubeconfigNamer) names(ctx context.Context, clusterId, m8ClusterName, clusterRole string) (*kubeconfigNames, error) {
+	if len(m8ClusterName) < 3 {
+		return nil, errors.New("clustername is too short")
+	}
+	if len(clusterRole) < 3 {
+		return nil, errors.New("clusterRole is too short")
+	}
+
+	clusterName, err := mk8s.GetK8sName(m8ClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &kubeconfigNameData{
+		Cluster: clusterName,
+		Role:    clusterRole,
+		User:    n.user,
+		tenant: func() (string, error) {
+			return n.getTenant(ctx, clusterId, m8ClusterName)
+		},
+	}
+	names := &kubeconfigNames{cluster: clusterName}
+	if names.context, err = renderName(n.contextTemplate, data); err != nil {
+		return nil, err
+	}
+	if names.authInfo, err = renderName(n.authInfoTemplate, data); err != nil {
+		return nil, err
+	}
+	if names.namespace, err = renderName(n.namespaceTemplate, data); err != nil {
+		return nil, err
+	}
+
+	owner := fmt.Sprintf("%s/%s", m8ClusterName, clusterRole)
+	if other, ok := n.contextOwners[names.context]; ok && other != owner {
+		return nil, fmt.Errorf("context name '%s' of %s collides with the one of %s", names.context, owner, other)
+	}
+	if other, ok := n.authInfoOwners[names.authInfo]; ok && other != owner {
+		return nil, fmt.Errorf("user name '%s' of %s collides with the one of %s", names.authInfo, owner, other)
+	}
+	n.contextOwners[names.context] = owner
+	n.authInfoOwners[names.authInfo] = owner
+
+	return names, nil
+}
+
+// renderName executes the naming template and validates the result as kubernetes name
+func renderName(tmpl *template.Template, data *kubeconfigNameData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed rendering %s naming template: %w", tmpl.Name(), err)
+	}
+	name, err := mk8s.GetK8sName(buf.String())
+	if err != nil {
+		return "", fmt.Errorf("%s naming template rendered invalid name '%s': %w", tmpl.Name(), buf.String(), err)
+	}
+	return name, nil
+}
+
+// getTenant returns the name of the tenant the cluster belongs to.
+// If the cluster belongs to multiple tenants the first name in alphabetical order is used.
+func (n *kubeconfigNamer) getTenant(ctx context.Context, clusterId, clusterName string) (string, error) {
+	if tenant, ok := n.tenants[clusterId]; ok {
+		return tenant, nil
+	}
+
+	bindingStream, err := n.clusterAccessClient.GetTenantClusterMappingsByClusterId(ctx, wrapperspb.String(clusterId))
+	if err != nil {
+		return "", err
+	}
+	var tenantNames []string
+	for {
+		binding, err := bindingStream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		tenant, err := n.tenantClient.GetById(ctx, wrapperspb.String(binding.TenantId))
+		if err != nil {
+			return "", err
+		}
+		tenantNames = append(tenantNames, tenant.Name)
+	}
+	if len(tenantNames) == 0 {
+		return "", fmt.Errorf("cluster '%s' does not belong to any tenant", clusterName)
+	}
+
+	sort.Strings(tenantNames)
+	n.tenants[clusterId] = tenantNames[0]
+	return tenantNames[0], nil
+}
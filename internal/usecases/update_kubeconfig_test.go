@@ -15,6 +15,7 @@
 package usecases
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
 	"io"
@@ -31,6 +32,11 @@ import (
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/zalando/go-keyring"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	kapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 var _ = Describe("UpdateKubeconfig", func() {
@@ -45,6 +51,7 @@ var _ = Describe("UpdateKubeconfig", func() {
 
 	BeforeEach(func() {
 		mockCtrl = gomock.NewController(GinkgoT())
+		keyring.MockInit()
 
 		var err error
 		m8TmpFile, err = os.CreateTemp("", "monoskope")
@@ -58,6 +65,10 @@ var _ = Describe("UpdateKubeconfig", func() {
 
 		os.Remove(m8TmpFile.Name())
 		os.Remove(kubeTmpFile.Name())
+
+		backups := k8s.NewKubeConfig()
+		backups.SetPath(kubeTmpFile.Name())
+		os.RemoveAll(backups.BackupDir())
 	})
 
 	var (
@@ -93,7 +104,7 @@ var _ = Describe("UpdateKubeconfig", func() {
 
 		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
 
-		uc := NewUpdateKubeconfigUseCase(configManager, "", true).(*UpdateKubeconfigUseCase)
+		uc := NewUpdateKubeconfigUseCase(configManager, "", true, false, nil).(*UpdateKubeconfigUseCase)
 		uc.clusterAccessClient = mockClusterAccessClient
 		uc.kubeConfig = k8s.NewKubeConfig()
 		uc.kubeConfig.SetPath(kubeTmpFile.Name())
@@ -148,7 +159,7 @@ var _ = Describe("UpdateKubeconfig", func() {
 
 		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
 
-		uc := NewUpdateKubeconfigUseCase(configManager, "", true).(*UpdateKubeconfigUseCase)
+		uc := NewUpdateKubeconfigUseCase(configManager, "", true, false, nil).(*UpdateKubeconfigUseCase)
 		uc.clusterAccessClient = mockClusterAccessClient
 		uc.kubeConfig = k8s.NewKubeConfig()
 		uc.setInitialized()
@@ -173,7 +184,7 @@ var _ = Describe("UpdateKubeconfig", func() {
 
 		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
 
-		uc := NewUpdateKubeconfigUseCase(configManager, kubeTmpFile.Name(), true).(*UpdateKubeconfigUseCase)
+		uc := NewUpdateKubeconfigUseCase(configManager, kubeTmpFile.Name(), true, false, nil).(*UpdateKubeconfigUseCase)
 		uc.clusterAccessClient = mockClusterAccessClient
 		uc.kubeConfig = k8s.NewKubeConfig()
 		uc.setInitialized()
@@ -190,4 +201,436 @@ var _ = Describe("UpdateKubeconfig", func() {
 		config := configManager.GetConfig()
 		Expect(config.KubeConfigPath).To(Equal(kubeTmpFile.Name()))
 	})
+
+	Context("changes", func() {
+		var (
+			configManager           *config.ClientConfigManager
+			mockClusterAccessClient *mdomain.MockClusterAccessClient
+		)
+
+		BeforeEach(func() {
+			conf := newConfig()
+			configManager = config.NewLoaderFromExplicitFile(m8TmpFile.Name())
+			Expect(configManager.SaveToFile(conf, m8TmpFile.Name(), 0644)).To(Succeed())
+			Expect(configManager.LoadConfig()).To(Succeed())
+
+			mockClusterAccessClient = mdomain.NewMockClusterAccessClient(mockCtrl)
+		})
+
+		newUseCase := func(dryRun bool) (*UpdateKubeconfigUseCase, *bytes.Buffer) {
+			uc := NewUpdateKubeconfigUseCase(configManager, kubeTmpFile.Name(), false, dryRun, nil).(*UpdateKubeconfigUseCase)
+			uc.clusterAccessClient = mockClusterAccessClient
+			uc.kubeConfig = k8s.NewKubeConfig()
+			uc.setInitialized()
+			buf := new(bytes.Buffer)
+			uc.out = buf
+			return uc, buf
+		}
+
+		expectClusterAccess := func(apiServerAddress string) {
+			getClusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+			getClusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+				Cluster: &projections.Cluster{
+					Id:               expectedId.String(),
+					Name:             expectedName,
+					ApiServerAddress: apiServerAddress,
+					CaCertBundle:     expectedClusterCACertBundle,
+				},
+				ClusterRoles: []*projections.ClusterRole{{Scope: projections.ClusterRole_CLUSTER, Role: string(roles.User)}},
+			}, nil)
+			getClusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+			mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(getClusterAccessClient, nil)
+		}
+
+		writeExistingKubeconfig := func() []byte {
+			kubeConfig := kapi.NewConfig()
+			kubeConfig.Clusters["gone-cluster"] = &kapi.Cluster{Server: "gone.cluster.monoskope.io"}
+			kubeConfig.Contexts["gone-cluster-user"] = &kapi.Context{Cluster: "gone-cluster", AuthInfo: "gone-cluster-jane-doe-user"}
+			kubeConfig.AuthInfos["gone-cluster-jane-doe-user"] = &kapi.AuthInfo{Exec: &kapi.ExecConfig{Command: "monoctl"}}
+			kubeConfig.Clusters["private"] = &kapi.Cluster{Server: "private.example.com"}
+			kubeConfig.Contexts["private"] = &kapi.Context{Cluster: "private", AuthInfo: "private"}
+			kubeConfig.AuthInfos["private"] = &kapi.AuthInfo{Token: "private-token"}
+			Expect(clientcmd.WriteToFile(*kubeConfig, kubeTmpFile.Name())).To(Succeed())
+
+			data, err := os.ReadFile(kubeTmpFile.Name())
+			Expect(err).ToNot(HaveOccurred())
+			return data
+		}
+
+		It("prints a diff without writing the kubeconfig on dry-run", func() {
+			original := writeExistingKubeconfig()
+			expectClusterAccess(expectedApiServerAddress)
+
+			uc, buf := newUseCase(true)
+			Expect(uc.Run(ctx)).To(Succeed())
+
+			output := buf.String()
+			Expect(output).To(ContainSubstring("--- " + kubeTmpFile.Name()))
+			Expect(output).To(ContainSubstring("-    server: gone.cluster.monoskope.io"))
+			Expect(output).To(ContainSubstring("+    server: " + expectedApiServerAddress))
+			Expect(output).ToNot(ContainSubstring("-    server: private.example.com"))
+			Expect(output).To(ContainSubstring("Context '" + expectedKubeContextName + "' added."))
+			Expect(output).To(ContainSubstring("Context 'gone-cluster-user' removed, lost access to cluster 'gone-cluster'."))
+			Expect(output).ToNot(ContainSubstring("Your kubeconfig has been updated."))
+
+			data, err := os.ReadFile(kubeTmpFile.Name())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(Equal(original))
+		})
+
+		It("prints the whole kubeconfig as added on dry-run if it does not exist yet", func() {
+			Expect(os.Remove(kubeTmpFile.Name())).To(Succeed())
+			expectClusterAccess(expectedApiServerAddress)
+
+			uc, buf := newUseCase(true)
+			Expect(uc.Run(ctx)).To(Succeed())
+
+			output := buf.String()
+			Expect(output).ToNot(ContainSubstring("No changes."))
+			Expect(output).To(ContainSubstring("+    server: " + expectedApiServerAddress))
+			Expect(output).To(ContainSubstring("Context '" + expectedKubeContextName + "' added."))
+
+			_, err := os.Stat(kubeTmpFile.Name())
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("reports updated contexts", func() {
+			expectClusterAccess(expectedApiServerAddress)
+			uc, _ := newUseCase(false)
+			Expect(uc.Run(ctx)).To(Succeed())
+
+			expectClusterAccess("new.cluster.monoskope.io")
+			uc, buf := newUseCase(false)
+			Expect(uc.Run(ctx)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("Context '" + expectedKubeContextName + "' updated."))
+
+			expectClusterAccess("new.cluster.monoskope.io")
+			uc, buf = newUseCase(true)
+			Expect(uc.Run(ctx)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("No changes."))
+			Expect(buf.String()).To(ContainSubstring("No contexts changed."))
+		})
+	})
+	Context("selection", func() {
+		var (
+			configManager           *config.ClientConfigManager
+			mockClusterAccessClient *mdomain.MockClusterAccessClient
+			mockTenantClient        *mdomain.MockTenantClient
+			otherClusterId          = uuid.New()
+		)
+
+		BeforeEach(func() {
+			conf := newConfig()
+			configManager = config.NewLoaderFromExplicitFile(m8TmpFile.Name())
+			Expect(configManager.SaveToFile(conf, m8TmpFile.Name(), 0644)).To(Succeed())
+			Expect(configManager.LoadConfig()).To(Succeed())
+
+			mockClusterAccessClient = mdomain.NewMockClusterAccessClient(mockCtrl)
+			mockTenantClient = mdomain.NewMockTenantClient(mockCtrl)
+		})
+
+		newUseCase := func(selection *config.KubeconfigSelection) *UpdateKubeconfigUseCase {
+			uc := NewUpdateKubeconfigUseCase(configManager, kubeTmpFile.Name(), false, false, selection).(*UpdateKubeconfigUseCase)
+			uc.clusterAccessClient = mockClusterAccessClient
+			uc.tenantClient = mockTenantClient
+			uc.kubeConfig = k8s.NewKubeConfig()
+			uc.setInitialized()
+			uc.out = new(bytes.Buffer)
+			return uc
+		}
+
+		expectClusterAccess := func() {
+			getClusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+			getClusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+				Cluster: &projections.Cluster{
+					Id:               expectedId.String(),
+					Name:             expectedName,
+					ApiServerAddress: expectedApiServerAddress,
+					CaCertBundle:     expectedClusterCACertBundle,
+				},
+				ClusterRoles: []*projections.ClusterRole{
+					{Scope: projections.ClusterRole_CLUSTER, Role: string(roles.User)},
+					{Scope: projections.ClusterRole_CLUSTER, Role: string(roles.Admin)},
+				},
+			}, nil)
+			getClusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+				Cluster: &projections.Cluster{
+					Id:               otherClusterId.String(),
+					Name:             "other-cluster",
+					ApiServerAddress: "other.cluster.monoskope.io",
+				},
+				ClusterRoles: []*projections.ClusterRole{{Scope: projections.ClusterRole_CLUSTER, Role: string(roles.User)}},
+			}, nil)
+			getClusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+			mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(getClusterAccessClient, nil)
+		}
+
+		loadKubeconfig := func() *kapi.Config {
+			kubeConfig, err := clientcmd.LoadFromFile(kubeTmpFile.Name())
+			Expect(err).ToNot(HaveOccurred())
+			return kubeConfig
+		}
+
+		It("leaves entries outside of the selection untouched and remembers the selection", func() {
+			kubeConfig := kapi.NewConfig()
+			kubeConfig.Clusters[expectedKubeClusterName] = &kapi.Cluster{Server: "old.cluster.monoskope.io"}
+			kubeConfig.Contexts["test-cluster-admin"] = &kapi.Context{Cluster: expectedKubeClusterName, AuthInfo: "test-cluster-jane-doe-admin"}
+			kubeConfig.AuthInfos["test-cluster-jane-doe-admin"] = &kapi.AuthInfo{Exec: &kapi.ExecConfig{
+				Command: "monoctl",
+				Args:    []string{"get", "cluster-credentials", expectedId.String(), string(roles.Admin)},
+			}}
+			Expect(clientcmd.WriteToFile(*kubeConfig, kubeTmpFile.Name())).To(Succeed())
+
+			expectClusterAccess()
+			Expect(newUseCase(&config.KubeconfigSelection{
+				Roles:   []string{string(roles.User)},
+				Exclude: []string{"other-*"},
+			}).Run(ctx)).To(Succeed())
+
+			kubeConfig = loadKubeconfig()
+			Expect(kubeConfig.Contexts).To(HaveKey(expectedKubeContextName))
+			Expect(kubeConfig.Contexts).To(HaveKey("test-cluster-admin"))
+			Expect(kubeConfig.AuthInfos).To(HaveKey("test-cluster-jane-doe-admin"))
+			Expect(kubeConfig.Contexts).ToNot(HaveKey("other-cluster-user"))
+
+			Expect(configManager.LoadConfig()).To(Succeed())
+			Expect(configManager.GetConfig().KubeconfigSelection).ToNot(BeNil())
+			Expect(configManager.GetConfig().KubeconfigSelection.Roles).To(ConsistOf(string(roles.User)))
+
+			// the remembered selection is used without a new one
+			expectClusterAccess()
+			Expect(newUseCase(nil).Run(ctx)).To(Succeed())
+			Expect(loadKubeconfig().Contexts).ToNot(HaveKey("other-cluster-user"))
+
+			// an empty selection resets it
+			expectClusterAccess()
+			Expect(newUseCase(&config.KubeconfigSelection{}).Run(ctx)).To(Succeed())
+			Expect(loadKubeconfig().Contexts).To(HaveKey("other-cluster-user"))
+			Expect(configManager.LoadConfig()).To(Succeed())
+			Expect(configManager.GetConfig().KubeconfigSelection).To(BeNil())
+		})
+
+		It("filters clusters by tenant", func() {
+			expectClusterAccess()
+
+			tenantId := uuid.New().String()
+			getAllTenantsClient := mdomain.NewMockTenant_GetAllClient(mockCtrl)
+			getAllTenantsClient.EXPECT().Recv().Return(&projections.Tenant{Id: tenantId, Name: "team-a"}, nil)
+			getAllTenantsClient.EXPECT().Recv().Return(&projections.Tenant{Id: uuid.New().String(), Name: "another-team"}, nil)
+			getAllTenantsClient.EXPECT().Recv().Return(nil, io.EOF)
+			mockTenantClient.EXPECT().GetAll(ctx, gomock.Any()).Return(getAllTenantsClient, nil)
+
+			getMappingsClient := mdomain.NewMockClusterAccess_GetTenantClusterMappingsByTenantIdClient(mockCtrl)
+			getMappingsClient.EXPECT().Recv().Return(&projections.TenantClusterBinding{TenantId: tenantId, ClusterId: otherClusterId.String()}, nil)
+			getMappingsClient.EXPECT().Recv().Return(nil, io.EOF)
+			mockClusterAccessClient.EXPECT().GetTenantClusterMappingsByTenantId(ctx, wrapperspb.String(tenantId)).Return(getMappingsClient, nil)
+
+			Expect(newUseCase(&config.KubeconfigSelection{Tenants: []string{"team-*"}}).Run(ctx)).To(Succeed())
+
+			kubeConfig := loadKubeconfig()
+			Expect(kubeConfig.Contexts).To(HaveKey("other-cluster-user"))
+			Expect(kubeConfig.Contexts).ToNot(HaveKey(expectedKubeContextName))
+			Expect(kubeConfig.Contexts).ToNot(HaveKey("test-cluster-admin"))
+		})
+	})
+	Context("naming", func() {
+		var (
+			configManager           *config.ClientConfigManager
+			mockClusterAccessClient *mdomain.MockClusterAccessClient
+			mockTenantClient        *mdomain.MockTenantClient
+		)
+
+		BeforeEach(func() {
+			mockClusterAccessClient = mdomain.NewMockClusterAccessClient(mockCtrl)
+			mockTenantClient = mdomain.NewMockTenantClient(mockCtrl)
+		})
+
+		newUseCase := func(naming *config.KubeconfigNaming) (*UpdateKubeconfigUseCase, *bytes.Buffer) {
+			conf := newConfig()
+			conf.KubeconfigNaming = naming
+			configManager = config.NewLoaderFromExplicitFile(m8TmpFile.Name())
+			Expect(configManager.SaveToFile(conf, m8TmpFile.Name(), 0644)).To(Succeed())
+			Expect(configManager.LoadConfig()).To(Succeed())
+
+			uc := NewUpdateKubeconfigUseCase(configManager, kubeTmpFile.Name(), false, false, nil).(*UpdateKubeconfigUseCase)
+			uc.clusterAccessClient = mockClusterAccessClient
+			uc.tenantClient = mockTenantClient
+			uc.kubeConfig = k8s.NewKubeConfig()
+			uc.setInitialized()
+			buf := new(bytes.Buffer)
+			uc.out = buf
+			return uc, buf
+		}
+
+		expectClusterAccess := func(clusterRoles ...string) {
+			access := &projections.ClusterAccessV2{
+				Cluster: &projections.Cluster{
+					Id:               expectedId.String(),
+					Name:             expectedName,
+					ApiServerAddress: expectedApiServerAddress,
+					CaCertBundle:     expectedClusterCACertBundle,
+				},
+			}
+			for _, role := range clusterRoles {
+				access.ClusterRoles = append(access.ClusterRoles, &projections.ClusterRole{Scope: projections.ClusterRole_CLUSTER, Role: role})
+			}
+			getClusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+			getClusterAccessClient.EXPECT().Recv().Return(access, nil)
+			getClusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+			mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(getClusterAccessClient, nil)
+		}
+
+		It("renders the configured templates", func() {
+			expectClusterAccess(string(roles.User))
+
+			tenantId := uuid.New().String()
+			getMappingsClient := mdomain.NewMockClusterAccess_GetTenantClusterMappingsByClusterIdClient(mockCtrl)
+			getMappingsClient.EXPECT().Recv().Return(&projections.TenantClusterBinding{TenantId: tenantId, ClusterId: expectedId.String()}, nil)
+			getMappingsClient.EXPECT().Recv().Return(nil, io.EOF)
+			mockClusterAccessClient.EXPECT().GetTenantClusterMappingsByClusterId(ctx, wrapperspb.String(expectedId.String())).Return(getMappingsClient, nil)
+			mockTenantClient.EXPECT().GetById(ctx, wrapperspb.String(tenantId)).Return(&projections.Tenant{Id: tenantId, Name: "Team_A"}, nil)
+
+			uc, _ := newUseCase(&config.KubeconfigNaming{
+				Context:   "{{.Cluster}}/{{.Role}}",
+				Namespace: "{{.Tenant}}-dev",
+			})
+			Expect(uc.Run(ctx)).To(Succeed())
+
+			kubeConfig, err := clientcmd.LoadFromFile(kubeTmpFile.Name())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kubeConfig.Contexts).To(HaveKey("test-cluster-user"))
+			Expect(kubeConfig.Contexts["test-cluster-user"].Namespace).To(Equal("team-a-dev"))
+			Expect(kubeConfig.Contexts["test-cluster-user"].AuthInfo).To(Equal(expectedAuthInfoName))
+		})
+
+		It("fails if rendered names collide", func() {
+			expectClusterAccess(string(roles.User), string(roles.Admin))
+
+			uc, _ := newUseCase(&config.KubeconfigNaming{Context: "{{.Cluster}}"})
+			err := uc.Run(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("context name 'test-cluster'"))
+		})
+
+		It("reports renamed contexts", func() {
+			expectClusterAccess(string(roles.User))
+			uc, _ := newUseCase(nil)
+			Expect(uc.Run(ctx)).To(Succeed())
+
+			kubeConfig, err := clientcmd.LoadFromFile(kubeTmpFile.Name())
+			Expect(err).ToNot(HaveOccurred())
+			kubeConfig.CurrentContext = expectedKubeContextName
+			Expect(clientcmd.WriteToFile(*kubeConfig, kubeTmpFile.Name())).To(Succeed())
+
+			expectClusterAccess(string(roles.User))
+			uc, buf := newUseCase(&config.KubeconfigNaming{Context: "m8-{{.Cluster}}-{{.Role}}"})
+			Expect(uc.Run(ctx)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("Context '" + expectedKubeContextName + "' renamed to 'm8-test-cluster-user'."))
+			Expect(buf.String()).ToNot(ContainSubstring("removed"))
+
+			kubeConfig, err = clientcmd.LoadFromFile(kubeTmpFile.Name())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kubeConfig.Contexts).ToNot(HaveKey(expectedKubeContextName))
+			Expect(kubeConfig.CurrentContext).To(Equal("m8-test-cluster-user"))
+		})
+	})
+	Context("customizations", func() {
+		var (
+			configManager           *config.ClientConfigManager
+			mockClusterAccessClient *mdomain.MockClusterAccessClient
+		)
+
+		BeforeEach(func() {
+			conf := newConfig()
+			configManager = config.NewLoaderFromExplicitFile(m8TmpFile.Name())
+			Expect(configManager.SaveToFile(conf, m8TmpFile.Name(), 0644)).To(Succeed())
+			Expect(configManager.LoadConfig()).To(Succeed())
+
+			mockClusterAccessClient = mdomain.NewMockClusterAccessClient(mockCtrl)
+		})
+
+		run := func() {
+			getClusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+			getClusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+				Cluster: &projections.Cluster{
+					Id:               expectedId.String(),
+					Name:             expectedName,
+					ApiServerAddress: expectedApiServerAddress,
+					CaCertBundle:     expectedClusterCACertBundle,
+				},
+				ClusterRoles: []*projections.ClusterRole{{Scope: projections.ClusterRole_CLUSTER, Role: string(roles.User)}},
+			}, nil)
+			getClusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+			mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(getClusterAccessClient, nil)
+
+			uc := NewUpdateKubeconfigUseCase(configManager, kubeTmpFile.Name(), false, false, nil).(*UpdateKubeconfigUseCase)
+			uc.clusterAccessClient = mockClusterAccessClient
+			uc.kubeConfig = k8s.NewKubeConfig()
+			uc.setInitialized()
+			uc.out = new(bytes.Buffer)
+			Expect(uc.Run(ctx)).To(Succeed())
+		}
+
+		It("marks managed entries and keeps the changes of the user", func() {
+			run()
+
+			kubeConfig, err := clientcmd.LoadFromFile(kubeTmpFile.Name())
+			Expect(err).ToNot(HaveOccurred())
+			kctx := kubeConfig.Contexts[expectedKubeContextName]
+			Expect(k8s.IsManaged(kctx.Extensions)).To(BeTrue())
+			Expect(k8s.IsManaged(kubeConfig.Clusters[expectedKubeClusterName].Extensions)).To(BeTrue())
+			Expect(k8s.IsManaged(kubeConfig.AuthInfos[expectedAuthInfoName].Extensions)).To(BeTrue())
+			exec := kubeConfig.AuthInfos[expectedAuthInfoName].Exec
+			Expect(exec.APIVersion).To(Equal(config.DefaultExecCredentialAPIVersion))
+			Expect(exec.InteractiveMode).To(Equal(kapi.IfAvailableExecInteractiveMode))
+			Expect(exec.ProvideClusterInfo).To(BeTrue())
+			Expect(kubeConfig.Clusters[expectedKubeClusterName].Extensions).To(HaveKey(k8s.ExecClusterExtension))
+
+			kctx.Namespace = "foo"
+			kctx.Extensions["example.com/color"] = &runtime.Unknown{Raw: []byte(`{"color":"red"}`), ContentType: runtime.ContentTypeJSON}
+			kubeConfig.AuthInfos[expectedAuthInfoName].Exec.Command = "/opt/bin/monoctl"
+			Expect(clientcmd.WriteToFile(*kubeConfig, kubeTmpFile.Name())).To(Succeed())
+
+			run()
+
+			kubeConfig, err = clientcmd.LoadFromFile(kubeTmpFile.Name())
+			Expect(err).ToNot(HaveOccurred())
+			kctx = kubeConfig.Contexts[expectedKubeContextName]
+			Expect(kctx.Namespace).To(Equal("foo"))
+			Expect(kctx.Extensions).To(HaveKey("example.com/color"))
+			Expect(kubeConfig.AuthInfos[expectedAuthInfoName].Exec.Command).To(Equal("/opt/bin/monoctl"))
+			Expect(kubeConfig.AuthInfos).To(HaveLen(1))
+		})
+
+		It("does not overwrite unmanaged contexts with a generated name", func() {
+			run()
+
+			kubeConfig, err := clientcmd.LoadFromFile(kubeTmpFile.Name())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kubeConfig.Contexts[expectedKubeContextName].Namespace).To(Equal(expectedNamespaceName))
+
+			// an unmanaged context with the name of a generated one is not overwritten
+			delete(kubeConfig.Contexts[expectedKubeContextName].Extensions, k8s.ManagedExtension)
+			kubeConfig.AuthInfos["private"] = &kapi.AuthInfo{Token: "private-token"}
+			kubeConfig.Contexts[expectedKubeContextName].AuthInfo = "private"
+			Expect(clientcmd.WriteToFile(*kubeConfig, kubeTmpFile.Name())).To(Succeed())
+
+			getClusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+			getClusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+				Cluster:      &projections.Cluster{Id: expectedId.String(), Name: expectedName, ApiServerAddress: expectedApiServerAddress},
+				ClusterRoles: []*projections.ClusterRole{{Scope: projections.ClusterRole_CLUSTER, Role: string(roles.User)}},
+			}, nil)
+			getClusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+			mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(getClusterAccessClient, nil)
+
+			uc := NewUpdateKubeconfigUseCase(configManager, kubeTmpFile.Name(), false, false, nil).(*UpdateKubeconfigUseCase)
+			uc.clusterAccessClient = mockClusterAccessClient
+			uc.kubeConfig = k8s.NewKubeConfig()
+			uc.setInitialized()
+			uc.out = new(bytes.Buffer)
+			err = uc.Run(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("collides with an existing context"))
+		})
+	})
 })
@@ -0,0 +1,261 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	mgrpc "github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/k8s"
+	"github.com/finleap-connect/monoctl/internal/prompt"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	projections "github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	ggrpc "google.golang.org/grpc"
+	kapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// UseContextUseCase switches the current context of the kubeconfig to a cluster and role
+type UseContextUseCase struct {
+	useCaseBase
+	conn                *ggrpc.ClientConn
+	configManager       *config.ClientConfigManager
+	clusterAccessClient api.ClusterAccessClient
+	userClient          api.UserClient
+	tenantClient        api.TenantClient
+	kubeConfig          *k8s.KubeConfig
+	clusterName         string
+	role                string
+	isInteractive       func() bool
+	selectItem          func(label string, items []string) (int, string, error)
+	out                 io.Writer
+}
+
+// NewUseContextUseCase returns the use-case switching to the context of the role on the cluster.
+// Without cluster name or role the user is asked to choose.
+func NewUseContextUseCase(configManager *config.ClientConfigManager, clusterName, role string) UseCase {
+	useCase := &UseContextUseCase{
+		useCaseBase:   NewUseCaseBase("use-context", configManager.GetConfig()),
+		configManager: configManager,
+		clusterName:   clusterName,
+		role:          role,
+		isInteractive: prompt.IsTerminal,
+		selectItem:    prompt.Select,
+		out:           os.Stdout,
+	}
+	return useCase
+}
+
+func (u *UseContextUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
+
+	conn, err := mgrpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if err != nil {
+		return err
+	}
+
+	u.conn = conn
+	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
+	u.userClient = api.NewUserClient(u.conn)
+	u.tenantClient = api.NewTenantClient(u.conn)
+
+	u.kubeConfig = k8s.NewKubeConfig()
+	u.setInitialized()
+
+	return nil
+}
+
+func (u *UseContextUseCase) run(ctx context.Context) error {
+	u.kubeConfig.SetPath(u.config.KubeConfigPath)
+	if u.config.KubeconfigBackupRetention != 0 {
+		u.kubeConfig.BackupRetention = u.config.KubeconfigBackupRetention
+	}
+	if err := u.kubeConfig.ResolvePath(); err != nil {
+		return err
+	}
+
+	update := NewUpdateKubeconfigUseCase(u.configManager, u.kubeConfig.ConfigPath, false, false, nil).(*UpdateKubeconfigUseCase)
+	update.clusterAccessClient = u.clusterAccessClient
+	update.userClient = u.userClient
+	update.tenantClient = u.tenantClient
+	update.kubeConfig = u.kubeConfig
+	update.out = u.out
+	update.setInitialized()
+
+	clusterAccesses, err := update.getClusterAccesses(ctx)
+	if err != nil {
+		return err
+	}
+	cluster, role, err := u.choose(clusterAccesses)
+	if err != nil {
+		return err
+	}
+
+	kubeConfig, err := u.kubeConfig.LoadConfig()
+	if err != nil {
+		return err
+	}
+	contextName, ok := findManagedContext(kubeConfig, cluster.Id, role)
+	if !ok {
+		// Refresh the kubeconfig to add the cluster
+		fmt.Fprintf(u.out, "No context for role '%s' on cluster '%s' found, updating kubeconfig.\n", role, cluster.Name)
+		if err := update.run(ctx); err != nil {
+			return err
+		}
+		update.printSummary()
+
+		if kubeConfig, err = u.kubeConfig.LoadConfig(); err != nil {
+			return err
+		}
+		if contextName, ok = findManagedContext(kubeConfig, cluster.Id, role); !ok {
+			return fmt.Errorf("no context for role '%s' on cluster '%s' in %s, it might be excluded by the kubeconfig selection, see `monoctl update kubeconfig --all`", role, cluster.Name, u.kubeConfig.ConfigPath)
+		}
+	}
+
+	if kubeConfig.CurrentContext != contextName {
+		kubeConfig.CurrentContext = contextName
+		if err := u.kubeConfig.StoreConfig(kubeConfig); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(u.out, "Switched to context '%s' (role '%s' on cluster '%s').\n", contextName, role, cluster.Name)
+
+	return nil
+}
+
+// choose returns the cluster and role given by the user or asks the user to select them
+func (u *UseContextUseCase) choose(clusterAccesses []*projections.ClusterAccessV2) (*projections.Cluster, string, error) {
+	if len(clusterAccesses) == 0 {
+		return nil, "", errors.New("you have no access to any cluster")
+	}
+
+	if u.clusterName == "" {
+		if !u.isInteractive() {
+			return nil, "", errors.New("no cluster given and no terminal to select one")
+		}
+		var items []string
+		var choices []*projections.ClusterAccessV2
+		var roles []string
+		for _, clusterAccess := range sortedClusterAccesses(clusterAccesses) {
+			for _, clusterRole := range clusterAccess.ClusterRoles {
+				items = append(items, fmt.Sprintf("%s %s", clusterAccess.Cluster.Name, clusterRole.Role))
+				choices = append(choices, clusterAccess)
+				roles = append(roles, clusterRole.Role)
+			}
+		}
+		idx, _, err := u.selectItem("Select cluster and role", items)
+		if err != nil {
+			return nil, "", err
+		}
+		return choices[idx].Cluster, roles[idx], nil
+	}
+
+	clusterAccess, err := findClusterAccess(clusterAccesses, u.clusterName)
+	if err != nil {
+		return nil, "", err
+	}
+	var roles []string
+	for _, clusterRole := range clusterAccess.ClusterRoles {
+		roles = append(roles, clusterRole.Role)
+	}
+	sort.Strings(roles)
+
+	if u.role != "" {
+		for _, role := range roles {
+			if role == u.role {
+				return clusterAccess.Cluster, role, nil
+			}
+		}
+		return nil, "", fmt.Errorf("you have no role '%s' on cluster '%s', you have roles %v", u.role, clusterAccess.Cluster.Name, roles)
+	}
+	if len(roles) == 1 {
+		return clusterAccess.Cluster, roles[0], nil
+	}
+	if !u.isInteractive() {
+		return nil, "", fmt.Errorf("you have roles %v on cluster '%s', specify one", roles, clusterAccess.Cluster.Name)
+	}
+	idx, _, err := u.selectItem(fmt.Sprintf("Select role on cluster %s", clusterAccess.Cluster.Name), roles)
+	if err != nil {
+		return nil, "", err
+	}
+	return clusterAccess.Cluster, roles[idx], nil
+}
+
+// findClusterAccess returns the access to the cluster with the given name, ignoring case if there is no exact match
+func findClusterAccess(clusterAccesses []*projections.ClusterAccessV2, clusterName string) (*projections.ClusterAccessV2, error) {
+	var match *projections.ClusterAccessV2
+	var names []string
+	for _, clusterAccess := range sortedClusterAccesses(clusterAccesses) {
+		if clusterAccess.Cluster.Name == clusterName {
+			return clusterAccess, nil
+		}
+		if match == nil && strings.EqualFold(clusterAccess.Cluster.Name, clusterName) {
+			match = clusterAccess
+		}
+		names = append(names, clusterAccess.Cluster.Name)
+	}
+	if match != nil {
+		return match, nil
+	}
+	return nil, fmt.Errorf("you have no access to cluster '%s', accessible clusters: %v", clusterName, names)
+}
+
+// sortedClusterAccesses returns the cluster accesses sorted by cluster name
+func sortedClusterAccesses(clusterAccesses []*projections.ClusterAccessV2) []*projections.ClusterAccessV2 {
+	sorted := append([]*projections.ClusterAccessV2{}, clusterAccesses...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Cluster.Name < sorted[j].Cluster.Name
+	})
+	return sorted
+}
+
+// findManagedContext returns the name of the monoctl managed context for the role on the cluster
+func findManagedContext(kubeConfig *kapi.Config, clusterId, role string) (string, bool) {
+	var contextNames []string
+	for contextName, kctx := range kubeConfig.Contexts {
+		authInfo, ok := kubeConfig.AuthInfos[kctx.AuthInfo]
+		if !ok || !isManagedAuthInfo(authInfo) {
+			continue
+		}
+		if id, r, ok := parseCredentialArgs(authInfo.Exec); ok && id == clusterId && r == role {
+			contextNames = append(contextNames, contextName)
+		}
+	}
+	if len(contextNames) == 0 {
+		return "", false
+	}
+	sort.Strings(contextNames)
+	return contextNames[0], true
+}
+
+func (u *UseContextUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	return u.run(ctx)
+}
@@ -0,0 +1,152 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/k8s"
+	mdomain "github.com/finleap-connect/monoctl/test/mock/domain"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/roles"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/zalando/go-keyring"
+	"k8s.io/client-go/tools/clientcmd"
+	kapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+var _ = Describe("UseContext", func() {
+	var (
+		ctx                     = context.Background()
+		mockCtrl                *gomock.Controller
+		mockClusterAccessClient *mdomain.MockClusterAccessClient
+		configManager           *config.ClientConfigManager
+		m8TmpFile               *os.File
+		kubeTmpFile             *os.File
+		clusterId               = uuid.New().String()
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		keyring.MockInit()
+		mockClusterAccessClient = mdomain.NewMockClusterAccessClient(mockCtrl)
+
+		var err error
+		m8TmpFile, err = os.CreateTemp("", "monoskope")
+		Expect(err).ToNot(HaveOccurred())
+		kubeTmpFile, err = os.CreateTemp("", "kubeconfig")
+		Expect(err).ToNot(HaveOccurred())
+
+		conf := config.NewConfig()
+		conf.Server = "m8.example.com"
+		conf.KubeConfigPath = kubeTmpFile.Name()
+		conf.AuthInformation = &config.AuthInformation{Token: "this-is-a-token", Username: "jane.doe"}
+		configManager = config.NewLoaderFromExplicitFile(m8TmpFile.Name())
+		Expect(configManager.SaveToFile(conf, m8TmpFile.Name(), 0644)).To(Succeed())
+		Expect(configManager.LoadConfig()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+		os.Remove(m8TmpFile.Name())
+		os.Remove(kubeTmpFile.Name())
+
+		backups := k8s.NewKubeConfig()
+		backups.SetPath(kubeTmpFile.Name())
+		os.RemoveAll(backups.BackupDir())
+	})
+
+	expectClusterAccess := func() {
+		getClusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+		getClusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+			Cluster: &projections.Cluster{Id: clusterId, Name: "test-cluster", ApiServerAddress: "test.cluster.monoskope.io"},
+			ClusterRoles: []*projections.ClusterRole{
+				{Scope: projections.ClusterRole_CLUSTER, Role: string(roles.User)},
+				{Scope: projections.ClusterRole_CLUSTER, Role: string(roles.Admin)},
+			},
+		}, nil)
+		getClusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(getClusterAccessClient, nil)
+	}
+
+	newUseCase := func(clusterName, role string) (*UseContextUseCase, *bytes.Buffer) {
+		uc := NewUseContextUseCase(configManager, clusterName, role).(*UseContextUseCase)
+		uc.clusterAccessClient = mockClusterAccessClient
+		uc.kubeConfig = k8s.NewKubeConfig()
+		uc.isInteractive = func() bool { return false }
+		uc.setInitialized()
+		buf := new(bytes.Buffer)
+		uc.out = buf
+		return uc, buf
+	}
+
+	loadKubeconfig := func() *kapi.Config {
+		kubeConfig, err := clientcmd.LoadFromFile(kubeTmpFile.Name())
+		Expect(err).ToNot(HaveOccurred())
+		return kubeConfig
+	}
+
+	It("switches to the context of the cluster and role", func() {
+		kubeConfig := kapi.NewConfig()
+		kubeConfig.Contexts["my-admin-context"] = &kapi.Context{Cluster: "test-cluster", AuthInfo: "admin"}
+		kubeConfig.AuthInfos["admin"] = &kapi.AuthInfo{Exec: &kapi.ExecConfig{
+			Command: "monoctl",
+			Args:    []string{"get", "cluster-credentials", clusterId, string(roles.Admin)},
+		}}
+		Expect(clientcmd.WriteToFile(*kubeConfig, kubeTmpFile.Name())).To(Succeed())
+
+		expectClusterAccess()
+		uc, buf := newUseCase("test-cluster", string(roles.Admin))
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("Switched to context 'my-admin-context'"))
+		Expect(loadKubeconfig().CurrentContext).To(Equal("my-admin-context"))
+	})
+
+	It("updates the kubeconfig if the cluster is missing", func() {
+		expectClusterAccess()
+		expectClusterAccess()
+		uc, buf := newUseCase("TEST-CLUSTER", string(roles.User))
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("Context 'test-cluster-user' added."))
+		Expect(loadKubeconfig().CurrentContext).To(Equal("test-cluster-user"))
+	})
+
+	It("asks for the role only if possible", func() {
+		expectClusterAccess()
+		uc, _ := newUseCase("test-cluster", "")
+		err := uc.Run(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("you have roles [admin user] on cluster 'test-cluster', specify one"))
+
+		expectClusterAccess()
+		expectClusterAccess()
+		uc, _ = newUseCase("", "")
+		uc.isInteractive = func() bool { return true }
+		uc.selectItem = func(label string, items []string) (int, string, error) {
+			Expect(items).To(Equal([]string{"test-cluster user", "test-cluster admin"}))
+			return 1, items[1], nil
+		}
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(loadKubeconfig().CurrentContext).To(Equal("test-cluster-admin"))
+	})
+})
@@ -0,0 +1,243 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	m8Grpc "github.com/finleap-connect/monoctl/internal/grpc"
+	"github.com/finleap-connect/monoctl/internal/jwt"
+	"github.com/finleap-connect/monoctl/internal/output"
+	api "github.com/finleap-connect/monoskope/pkg/api/domain"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/scopes"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type whoAmI struct {
+	Id           string               `json:"id"`
+	Name         string               `json:"name"`
+	Email        string               `json:"email"`
+	RoleBindings []*whoAmIRoleBinding `json:"roleBindings"`
+	Tenants      []*whoAmITenant      `json:"tenants"`
+	Clusters     []*whoAmICluster     `json:"clusters"`
+}
+
+type whoAmIRoleBinding struct {
+	Id       string `json:"id"`
+	Role     string `json:"role"`
+	Scope    string `json:"scope"`
+	Resource string `json:"resource,omitempty"`
+}
+
+type whoAmITenant struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+}
+
+type whoAmICluster struct {
+	Id    string   `json:"id"`
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+// whoAmIUseCase provides the internal use-case of showing the current user and what they have access to.
+type whoAmIUseCase struct {
+	useCaseBase
+	conn                *ggrpc.ClientConn
+	userClient          api.UserClient
+	tenantClient        api.TenantClient
+	clusterAccessClient api.ClusterAccessClient
+	outputFormat        output.OutputFormat
+	out                 io.Writer
+}
+
+func NewWhoAmIUseCase(config *config.Config, outputFormat output.OutputFormat) UseCase {
+	useCase := &whoAmIUseCase{
+		useCaseBase:  NewUseCaseBase("whoami", config),
+		outputFormat: outputFormat,
+		out:          os.Stdout,
+	}
+	return useCase
+}
+
+func (u *whoAmIUseCase) init(ctx context.Context) error {
+	if u.initialized {
+		return nil
+	}
+
+	conn, err := m8Grpc.CreateGrpcConnectionAuthenticatedFromConfig(ctx, u.config)
+	if err != nil {
+		return err
+	}
+
+	u.conn = conn
+	u.userClient = api.NewUserClient(u.conn)
+	u.tenantClient = api.NewTenantClient(u.conn)
+	u.clusterAccessClient = api.NewClusterAccessClient(u.conn)
+	u.setInitialized()
+
+	return nil
+}
+
+func (u *whoAmIUseCase) collect(ctx context.Context) (*whoAmI, error) {
+	// The email of the user is only known to the token issued by the m8 control plane
+	claims, err := jwt.ParseUnverified(u.config.AuthInformation.Token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.StandardClaims == nil || claims.Email == "" {
+		return nil, errors.New("token does not contain the email of the user")
+	}
+
+	user, err := u.userClient.GetByEmail(ctx, wrapperspb.String(claims.Email))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &whoAmI{
+		Id:           user.Id,
+		Name:         user.Name,
+		Email:        user.Email,
+		RoleBindings: []*whoAmIRoleBinding{},
+		Tenants:      []*whoAmITenant{},
+		Clusters:     []*whoAmICluster{},
+	}
+
+	roleBindingsStream, err := u.userClient.GetRoleBindingsById(ctx, wrapperspb.String(user.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	tenantNames := make(map[string]string)
+	for {
+		// Read next
+		rb, err := roleBindingsStream.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		roleBinding := &whoAmIRoleBinding{
+			Id:       rb.Id,
+			Role:     rb.Role,
+			Scope:    rb.Scope,
+			Resource: rb.Resource,
+		}
+		if rb.Scope == string(scopes.Tenant) {
+			if _, ok := tenantNames[rb.Resource]; !ok {
+				tenantNames[rb.Resource] = rb.Resource
+				// Rolebindings might be left on tenants deleted already, those are shown with their id
+				if tenant, err := u.tenantClient.GetById(ctx, wrapperspb.String(rb.Resource)); err == nil {
+					tenantNames[rb.Resource] = tenant.Name
+					result.Tenants = append(result.Tenants, &whoAmITenant{
+						Id:     tenant.Id,
+						Name:   tenant.Name,
+						Prefix: tenant.Prefix,
+					})
+				}
+			}
+			roleBinding.Resource = tenantNames[rb.Resource]
+		}
+		result.RoleBindings = append(result.RoleBindings, roleBinding)
+	}
+
+	clusterAccesses, err := u.clusterAccessClient.GetClusterAccessV2(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// Read next
+		clusterAccess, err := clusterAccesses.Recv()
+
+		// End of stream
+		if err == io.EOF {
+			break
+		}
+		if err != nil { // Some other error
+			return nil, err
+		}
+
+		cluster := &whoAmICluster{
+			Id:    clusterAccess.Cluster.Id,
+			Name:  clusterAccess.Cluster.Name,
+			Roles: []string{},
+		}
+		for _, clusterRole := range clusterAccess.ClusterRoles {
+			cluster.Roles = append(cluster.Roles, clusterRole.Role)
+		}
+		result.Clusters = append(result.Clusters, cluster)
+	}
+
+	return result, nil
+}
+
+func (u *whoAmIUseCase) render(result *whoAmI) error {
+	if u.outputFormat == output.JSONFormat {
+		return output.WriteJSON(u.out, result)
+	}
+
+	var roleBindings [][]interface{}
+	for _, rb := range result.RoleBindings {
+		roleBindings = append(roleBindings, []interface{}{rb.Role, rb.Scope, rb.Resource})
+	}
+	var tenants [][]interface{}
+	for _, tenant := range result.Tenants {
+		tenants = append(tenants, []interface{}{tenant.Name, tenant.Prefix})
+	}
+	var clusters [][]interface{}
+	for _, cluster := range result.Clusters {
+		clusters = append(clusters, []interface{}{cluster.Name, strings.Join(cluster.Roles, ", ")})
+	}
+
+	fields := []describeField{
+		{"Name", result.Name},
+		{"Email", result.Email},
+		{"ID", result.Id},
+	}
+	return renderDescription(u.out, fields, []describeSection{
+		{"Rolebindings", []string{"ROLE", "SCOPE", "RESOURCE"}, roleBindings},
+		{"Tenants", []string{"NAME", "PREFIX"}, tenants},
+		{"Clusters", []string{"CLUSTER", "ROLES"}, clusters},
+	})
+}
+
+func (u *whoAmIUseCase) Run(ctx context.Context) error {
+	err := u.init(ctx)
+	if err != nil {
+		return err
+	}
+	if u.conn != nil {
+		defer u.conn.Close()
+	}
+
+	result, err := u.collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.render(result)
+}
@@ -0,0 +1,211 @@
+// Copyright 2021 Monoskope Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/output"
+	mdomain "github.com/finleap-connect/monoctl/test/mock/domain"
+	"github.com/finleap-connect/monoskope/pkg/api/domain/projections"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/roles"
+	"github.com/finleap-connect/monoskope/pkg/domain/constants/scopes"
+	mjwt "github.com/finleap-connect/monoskope/pkg/jwt"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// newTestToken returns a signed token as issued by the m8 control plane containing the given claims
+func newTestToken(claims *mjwt.AuthToken) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("this-is-a-test-key-of-sufficient-length")}, nil)
+	Expect(err).ToNot(HaveOccurred())
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	Expect(err).ToNot(HaveOccurred())
+	return token
+}
+
+var _ = Describe("WhoAmI", func() {
+	var (
+		mockCtrl *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	var (
+		ctx          = context.Background()
+		expectedUser = &projections.User{
+			Id:    uuid.New().String(),
+			Name:  "Jane Doe",
+			Email: "jane.doe@monoskope.io",
+		}
+		expectedTenant = &projections.Tenant{
+			Id:     uuid.New().String(),
+			Name:   "the-tenant",
+			Prefix: "tt",
+		}
+		expectedCluster = &projections.Cluster{
+			Id:   uuid.New().String(),
+			Name: "the-cluster",
+		}
+	)
+
+	It("should collect user, rolebindings, tenants and clusters", func() {
+		conf := config.NewConfig()
+		conf.Server = "m8.example.com"
+		conf.AuthInformation = &config.AuthInformation{
+			Username: expectedUser.Name,
+			Token: newTestToken(&mjwt.AuthToken{
+				StandardClaims: &mjwt.StandardClaims{Name: expectedUser.Name, Email: expectedUser.Email},
+			}),
+		}
+
+		mockUserClient := mdomain.NewMockUserClient(mockCtrl)
+		mockTenantClient := mdomain.NewMockTenantClient(mockCtrl)
+		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
+
+		mockUserClient.EXPECT().GetByEmail(ctx, wrapperspb.String(expectedUser.Email)).Return(expectedUser, nil)
+
+		roleBindingsClient := mdomain.NewMockUser_GetRoleBindingsByIdClient(mockCtrl)
+		roleBindingsClient.EXPECT().Recv().Return(&projections.UserRoleBinding{
+			Id:     uuid.New().String(),
+			UserId: expectedUser.Id,
+			Role:   string(roles.User),
+			Scope:  string(scopes.System),
+		}, nil)
+		roleBindingsClient.EXPECT().Recv().Return(&projections.UserRoleBinding{
+			Id:       uuid.New().String(),
+			UserId:   expectedUser.Id,
+			Role:     string(roles.Admin),
+			Scope:    string(scopes.Tenant),
+			Resource: expectedTenant.Id,
+		}, nil)
+		roleBindingsClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockUserClient.EXPECT().GetRoleBindingsById(ctx, wrapperspb.String(expectedUser.Id)).Return(roleBindingsClient, nil)
+		mockTenantClient.EXPECT().GetById(ctx, wrapperspb.String(expectedTenant.Id)).Return(expectedTenant, nil)
+
+		clusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+		clusterAccessClient.EXPECT().Recv().Return(&projections.ClusterAccessV2{
+			Cluster: expectedCluster,
+			ClusterRoles: []*projections.ClusterRole{
+				{Scope: projections.ClusterRole_CLUSTER, Role: string(roles.User)},
+				{Scope: projections.ClusterRole_CLUSTER, Role: string(roles.Admin)},
+			},
+		}, nil)
+		clusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(clusterAccessClient, nil)
+
+		out := new(bytes.Buffer)
+		uc := NewWhoAmIUseCase(conf, output.JSONFormat).(*whoAmIUseCase)
+		uc.userClient = mockUserClient
+		uc.tenantClient = mockTenantClient
+		uc.clusterAccessClient = mockClusterAccessClient
+		uc.out = out
+		uc.setInitialized()
+
+		Expect(uc.Run(ctx)).To(Succeed())
+
+		result := new(whoAmI)
+		Expect(json.Unmarshal(out.Bytes(), result)).To(Succeed())
+		Expect(result.Email).To(Equal(expectedUser.Email))
+		Expect(result.RoleBindings).To(HaveLen(2))
+		Expect(result.RoleBindings[1].Resource).To(Equal(expectedTenant.Name))
+		Expect(result.Tenants).To(HaveLen(1))
+		Expect(result.Tenants[0].Name).To(Equal(expectedTenant.Name))
+		Expect(result.Clusters).To(HaveLen(1))
+		Expect(result.Clusters[0].Roles).To(ConsistOf(string(roles.User), string(roles.Admin)))
+
+		out.Reset()
+		uc.outputFormat = output.TableFormat
+		Expect(uc.render(result)).To(Succeed())
+		Expect(out.String()).To(MatchRegexp(`(?m)^Tenants:\nNAME\s+PREFIX\s*\n` + expectedTenant.Name + `\s+` + expectedTenant.Prefix + `\s*$`))
+	})
+
+	It("should show rolebindings on deleted tenants with their id", func() {
+		conf := config.NewConfig()
+		conf.Server = "m8.example.com"
+		conf.AuthInformation = &config.AuthInformation{
+			Username: expectedUser.Name,
+			Token: newTestToken(&mjwt.AuthToken{
+				StandardClaims: &mjwt.StandardClaims{Name: expectedUser.Name, Email: expectedUser.Email},
+			}),
+		}
+
+		mockUserClient := mdomain.NewMockUserClient(mockCtrl)
+		mockTenantClient := mdomain.NewMockTenantClient(mockCtrl)
+		mockClusterAccessClient := mdomain.NewMockClusterAccessClient(mockCtrl)
+
+		mockUserClient.EXPECT().GetByEmail(ctx, wrapperspb.String(expectedUser.Email)).Return(expectedUser, nil)
+
+		deletedTenantId := uuid.New().String()
+		roleBindingsClient := mdomain.NewMockUser_GetRoleBindingsByIdClient(mockCtrl)
+		roleBindingsClient.EXPECT().Recv().Return(&projections.UserRoleBinding{
+			Id:       uuid.New().String(),
+			UserId:   expectedUser.Id,
+			Role:     string(roles.Admin),
+			Scope:    string(scopes.Tenant),
+			Resource: deletedTenantId,
+		}, nil)
+		roleBindingsClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockUserClient.EXPECT().GetRoleBindingsById(ctx, wrapperspb.String(expectedUser.Id)).Return(roleBindingsClient, nil)
+		mockTenantClient.EXPECT().GetById(ctx, wrapperspb.String(deletedTenantId)).Return(nil, errors.New("tenant not found"))
+
+		clusterAccessClient := mdomain.NewMockClusterAccess_GetClusterAccessV2Client(mockCtrl)
+		clusterAccessClient.EXPECT().Recv().Return(nil, io.EOF)
+		mockClusterAccessClient.EXPECT().GetClusterAccessV2(ctx, &empty.Empty{}).Return(clusterAccessClient, nil)
+
+		out := new(bytes.Buffer)
+		uc := NewWhoAmIUseCase(conf, output.TableFormat).(*whoAmIUseCase)
+		uc.userClient = mockUserClient
+		uc.tenantClient = mockTenantClient
+		uc.clusterAccessClient = mockClusterAccessClient
+		uc.out = out
+		uc.setInitialized()
+
+		Expect(uc.Run(ctx)).To(Succeed())
+		Expect(out.String()).To(MatchRegexp(`(?m)^Email: ` + expectedUser.Email + `$`))
+		Expect(out.String()).To(MatchRegexp(`(?m)^ID:    ` + expectedUser.Id + `$`))
+		Expect(out.String()).To(MatchRegexp(`(?m)^admin\s+tenant\s+` + deletedTenantId + `\s*$`))
+	})
+
+	It("should fail for tokens without email", func() {
+		conf := config.NewConfig()
+		conf.Server = "m8.example.com"
+		conf.AuthInformation = &config.AuthInformation{
+			Token: newTestToken(&mjwt.AuthToken{}),
+		}
+
+		uc := NewWhoAmIUseCase(conf, output.TableFormat).(*whoAmIUseCase)
+		uc.setInitialized()
+
+		Expect(uc.Run(ctx)).ToNot(Succeed())
+	})
+})
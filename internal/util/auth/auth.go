@@ -16,24 +16,51 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"github.com/finleap-connect/monoctl/internal/spinner"
-	"github.com/juju/clock"
-	"github.com/juju/mutex/v2"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/finleap-connect/monoctl/cmd/monoctl/flags"
 	"github.com/finleap-connect/monoctl/internal/config"
+	"github.com/finleap-connect/monoctl/internal/spinner"
 	"github.com/finleap-connect/monoctl/internal/usecases"
+	"github.com/juju/clock"
+	"github.com/juju/mutex/v2"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 const (
-	authFlowNamedMutex         = "monoctl-auth-flow"
+	authFlowNamedMutexPrefix   = "monoctl-auth-"
 	namedMutexDetectionTimeOut = 500 * time.Millisecond
 )
 
+// LoginInProgressError is returned if the authentication lock could not be acquired because another process holds it
+type LoginInProgressError struct {
+	// Pid of the process holding the lock, zero if unknown
+	Pid int
+	// Err is the reason for giving up waiting for the lock
+	Err error
+}
+
+func (e *LoginInProgressError) Error() string {
+	if e.Pid > 0 {
+		return fmt.Sprintf("login in progress in another process (pid %d): %v", e.Pid, e.Err)
+	}
+	return fmt.Sprintf("login in progress in another process: %v", e.Err)
+}
+
+func (e *LoginInProgressError) Unwrap() error {
+	return e.Err
+}
+
 func RetryOnAuthFailSilently(ctx context.Context, configManager *config.ClientConfigManager, f func(ctx context.Context) error) error {
 	return retryOnAuthFail(ctx, configManager, true, f)
 }
@@ -43,8 +70,50 @@ func RetryOnAuthFail(ctx context.Context, configManager *config.ClientConfigMana
 }
 
 func retryOnAuthFail(ctx context.Context, configManager *config.ClientConfigManager, silent bool, f func(ctx context.Context) error) error {
+	return withAuthLock(ctx, configManager, silent, func(ctx context.Context) error {
+		if err := LoadConfigAndAuth(ctx, configManager, flags.ForceAuth, silent); err != nil {
+			return fmt.Errorf("init failed: %w", err)
+		}
+		if !silent {
+			warnIfTokenExpiresSoon(os.Stderr, configManager.GetConfig())
+		}
+
+		if err := f(ctx); err != nil {
+			status, ok := status.FromError(err)
+			if ok && status.Code() == codes.Unauthenticated {
+				if err := LoadConfigAndAuth(ctx, configManager, true, silent); err != nil {
+					return fmt.Errorf("init failed: %w", err)
+				}
+				return f(ctx)
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// Renew renews the session with the m8 control plane ahead of time, even if the current one is still valid
+func Renew(ctx context.Context, configManager *config.ClientConfigManager) error {
+	return withAuthLock(ctx, configManager, false, func(ctx context.Context) error {
+		return usecases.NewRenewAuthUsecase(configManager).Run(ctx)
+	})
+}
+
+// withAuthLock loads the config and runs f while holding the authentication lock of the configured server
+func withAuthLock(ctx context.Context, configManager *config.ClientConfigManager, silent bool, f func(ctx context.Context) error) error {
+	// The lock is scoped per server, so the config has to be known before acquiring it.
+	if err := configManager.LoadConfig(); err != nil {
+		return fmt.Errorf("failed loading monoconfig: %w", err)
+	}
+
 	// Make sure no other process run the authentication flow.
-	lock, err := acquireLock(ctx, silent)
+	lockCtx := ctx
+	if flags.Timeout > 0 {
+		var cancelLock context.CancelFunc
+		lockCtx, cancelLock = context.WithTimeout(ctx, flags.Timeout)
+		defer cancelLock()
+	}
+	lock, err := acquireLock(lockCtx, configManager.GetConfig().Server, silent)
 	if err != nil {
 		return err
 	}
@@ -53,21 +122,16 @@ func retryOnAuthFail(ctx context.Context, configManager *config.ClientConfigMana
 	ctx, cancel := context.WithTimeout(ctx, time.Minute*2) // special timeout for login flow with consent can take longer
 	defer cancel()
 
-	if err := LoadConfigAndAuth(ctx, configManager, flags.ForceAuth, silent); err != nil {
-		return fmt.Errorf("init failed: %w", err)
-	}
+	return f(ctx)
+}
 
-	if err := f(ctx); err != nil {
-		status, ok := status.FromError(err)
-		if ok && status.Code() == codes.Unauthenticated {
-			if err := LoadConfigAndAuth(ctx, configManager, true, silent); err != nil {
-				return fmt.Errorf("init failed: %w", err)
-			}
-			return f(ctx)
-		}
-		return err
+// warnIfTokenExpiresSoon writes a warning to w if the auth token expires within the configured warning window
+func warnIfTokenExpiresSoon(w io.Writer, conf *config.Config) {
+	if !conf.TokenExpiresSoon() {
+		return
 	}
-	return nil
+	expiresIn := time.Until(conf.AuthInformation.Expiry).Round(time.Minute)
+	fmt.Fprintf(w, "Warning: your session expires in %v (%v). Run `monoctl auth renew` to renew it ahead of time.\n", expiresIn, conf.AuthInformation.Expiry.Local().Format(time.RFC1123))
 }
 
 func LoadConfigAndAuth(ctx context.Context, configManager *config.ClientConfigManager, force, silent bool) error {
@@ -77,7 +141,71 @@ func LoadConfigAndAuth(ctx context.Context, configManager *config.ClientConfigMa
 	return usecases.NewAuthUsecase(configManager, force, silent).Run(ctx)
 }
 
-func acquireLock(_ context.Context, silent bool) (mutex.Releaser, error) {
+// authFlowMutexName returns the name of the mutex guarding the authentication flow against the given server
+func authFlowMutexName(server string) string {
+	hash := sha256.Sum256([]byte(server))
+	return authFlowNamedMutexPrefix + hex.EncodeToString(hash[:])[:16]
+}
+
+// lockOwnerFile returns the path of the file the pid of the process holding the named mutex is written to.
+// It lives in the cache dir of the user, so other users can neither read nor plant it.
+func lockOwnerFile(name string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "monoctl", name+".pid"), nil
+}
+
+// writeLockOwner writes the pid of the current process to the owner file of the named mutex
+func writeLockOwner(name string) error {
+	file, err := lockOwnerFile(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(strconv.Itoa(os.Getpid())), 0600)
+}
+
+// readLockOwner returns the pid of the process holding the named mutex or zero if unknown
+func readLockOwner(name string) int {
+	file, err := lockOwnerFile(name)
+	if err != nil {
+		return 0
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
+
+// authFlowLock releases the named mutex and removes the owner information
+type authFlowLock struct {
+	mutex.Releaser
+	name string
+}
+
+func (l *authFlowLock) Release() {
+	if file, err := lockOwnerFile(l.name); err == nil {
+		_ = os.Remove(file)
+	}
+	l.Releaser.Release()
+}
+
+// acquireLock acquires the cross-process lock for the authentication flow against the given server.
+// It blocks until the lock is acquired or the context is done. The lock itself is held by the OS for the
+// lifetime of the process, so it can not go stale if a process dies. The owner information is overwritten
+// by every process acquiring the lock.
+func acquireLock(ctx context.Context, server string, silent bool) (mutex.Releaser, error) {
+	name := authFlowMutexName(server)
+
 	var s *spinner.Spinner
 	if !silent {
 		s = spinner.NewSpinner()
@@ -99,9 +227,23 @@ func acquireLock(_ context.Context, silent bool) (mutex.Releaser, error) {
 		}
 	}()
 
-	return mutex.Acquire(mutex.Spec{
-		Name:  authFlowNamedMutex,
-		Clock: clock.WallClock,
-		Delay: time.Second,
+	lock, err := mutex.Acquire(mutex.Spec{
+		Name:   name,
+		Clock:  clock.WallClock,
+		Delay:  time.Second,
+		Cancel: ctx.Done(),
 	})
+	if err != nil {
+		if errors.Is(err, mutex.ErrCancelled) {
+			return nil, &LoginInProgressError{Pid: readLockOwner(name), Err: ctx.Err()}
+		}
+		return nil, err
+	}
+
+	// The owner is informational for other processes waiting for the lock only, so failing to write it is not fatal.
+	if err := writeLockOwner(name); err != nil && !silent {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record the owner of the authentication lock: %v\n", err)
+	}
+
+	return &authFlowLock{Releaser: lock, name: name}, nil
 }
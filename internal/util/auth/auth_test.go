@@ -15,7 +15,9 @@
 package auth
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -23,16 +25,20 @@ import (
 
 	_ "embed"
 
+	"github.com/finleap-connect/monoctl/internal/config"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/gexec"
 )
 
+const testServer = "https://m8.example.com"
+
 var _ = Describe("Auth", func() {
 	Context("should not run multiple authentication flows simultaneously", func() {
 		Specify("FirstProcess", func() {
-			lock, err := acquireLock(context.Background(), false)
+			lock, err := acquireLock(context.Background(), testServer, false)
 			Expect(err).ToNot(HaveOccurred())
 
 			ex, err := os.Executable()
@@ -60,8 +66,85 @@ var _ = Describe("Auth", func() {
 				Skip("This is to be run in a separate process")
 			}
 
-			_, err := acquireLock(context.Background(), false)
+			_, err := acquireLock(context.Background(), testServer, false)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+	Context("should respect the context when waiting for the lock", func() {
+		It("returns an error naming the process holding the lock", func() {
+			lock, err := acquireLock(context.Background(), testServer, true)
+			Expect(err).ToNot(HaveOccurred())
+			defer lock.Release()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*namedMutexDetectionTimeOut)
+			defer cancel()
+
+			_, err = acquireLock(ctx, testServer, true)
+			Expect(err).To(HaveOccurred())
+
+			var loginInProgressErr *LoginInProgressError
+			Expect(errors.As(err, &loginInProgressErr)).To(BeTrue())
+			Expect(loginInProgressErr.Pid).To(Equal(os.Getpid()))
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+			Expect(err.Error()).To(ContainSubstring("login in progress in another process (pid"))
+		})
+		It("does not block instances using another server", func() {
+			lock, err := acquireLock(context.Background(), testServer, true)
 			Expect(err).ToNot(HaveOccurred())
+			defer lock.Release()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*namedMutexDetectionTimeOut)
+			defer cancel()
+
+			otherLock, err := acquireLock(ctx, "https://another.m8.example.com", true)
+			Expect(err).ToNot(HaveOccurred())
+			otherLock.Release()
+		})
+	})
+	Context("lock owner", func() {
+		It("is recorded in the cache dir of the user", func() {
+			cacheDir, err := os.MkdirTemp("", "monoctl-cache")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(cacheDir)
+
+			oldCacheHome, hadCacheHome := os.LookupEnv("XDG_CACHE_HOME")
+			Expect(os.Setenv("XDG_CACHE_HOME", cacheDir)).To(Succeed())
+			defer func() {
+				if hadCacheHome {
+					_ = os.Setenv("XDG_CACHE_HOME", oldCacheHome)
+				} else {
+					_ = os.Unsetenv("XDG_CACHE_HOME")
+				}
+			}()
+
+			lock, err := acquireLock(context.Background(), testServer, true)
+			Expect(err).ToNot(HaveOccurred())
+
+			ownerFile := filepath.Join(cacheDir, "monoctl", authFlowMutexName(testServer)+".pid")
+			Expect(ownerFile).To(BeARegularFile())
+			Expect(readLockOwner(authFlowMutexName(testServer))).To(Equal(os.Getpid()))
+
+			lock.Release()
+			Expect(ownerFile).ToNot(BeAnExistingFile())
+		})
+	})
+	Context("token expiry warning", func() {
+		It("warns if the token expires soon", func() {
+			conf := config.NewConfig()
+			conf.AuthInformation = &config.AuthInformation{Token: "test", Expiry: time.Now().Add(10 * time.Minute)}
+
+			buf := new(bytes.Buffer)
+			warnIfTokenExpiresSoon(buf, conf)
+			Expect(buf.String()).To(ContainSubstring("Warning: your session expires in"))
+			Expect(buf.String()).To(ContainSubstring("monoctl auth renew"))
+		})
+		It("does not warn if the token is valid long enough", func() {
+			conf := config.NewConfig()
+			conf.AuthInformation = &config.AuthInformation{Token: "test", Expiry: time.Now().Add(10 * time.Hour)}
+
+			buf := new(bytes.Buffer)
+			warnIfTokenExpiresSoon(buf, conf)
+			Expect(buf.String()).To(BeEmpty())
 		})
 	})
 })
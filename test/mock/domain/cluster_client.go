@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/finleap-connect/monoskope/pkg/api/domain (interfaces: ClusterClient,Cluster_GetAllClient,ClusterAccessClient,ClusterAccess_GetClusterAccessV2Client)
+// Source: github.com/finleap-connect/monoskope/pkg/api/domain (interfaces: ClusterClient,Cluster_GetAllClient,ClusterAccessClient,ClusterAccess_GetClusterAccessV2Client,ClusterAccess_GetTenantClusterMappingsByTenantIdClient,ClusterAccess_GetTenantClusterMappingsByClusterIdClient)
 
 // Package domain is a generated GoMock package.
 package domain
@@ -468,3 +468,249 @@ func (mr *MockClusterAccess_GetClusterAccessV2ClientMockRecorder) Trailer() *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockClusterAccess_GetClusterAccessV2Client)(nil).Trailer))
 }
+
+// MockClusterAccess_GetTenantClusterMappingsByTenantIdClient is a mock of ClusterAccess_GetTenantClusterMappingsByTenantIdClient interface.
+type MockClusterAccess_GetTenantClusterMappingsByTenantIdClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder
+}
+
+// MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder is the mock recorder for MockClusterAccess_GetTenantClusterMappingsByTenantIdClient.
+type MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder struct {
+	mock *MockClusterAccess_GetTenantClusterMappingsByTenantIdClient
+}
+
+// NewMockClusterAccess_GetTenantClusterMappingsByTenantIdClient creates a new mock instance.
+func NewMockClusterAccess_GetTenantClusterMappingsByTenantIdClient(ctrl *gomock.Controller) *MockClusterAccess_GetTenantClusterMappingsByTenantIdClient {
+	mock := &MockClusterAccess_GetTenantClusterMappingsByTenantIdClient{ctrl: ctrl}
+	mock.recorder = &MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClusterAccess_GetTenantClusterMappingsByTenantIdClient) EXPECT() *MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByTenantIdClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByTenantIdClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByTenantIdClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByTenantIdClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByTenantIdClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByTenantIdClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByTenantIdClient) Recv() (*projections.TenantClusterBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*projections.TenantClusterBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByTenantIdClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByTenantIdClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByTenantIdClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByTenantIdClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByTenantIdClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByTenantIdClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByTenantIdClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByTenantIdClient)(nil).Trailer))
+}
+
+// MockClusterAccess_GetTenantClusterMappingsByClusterIdClient is a mock of ClusterAccess_GetTenantClusterMappingsByClusterIdClient interface.
+type MockClusterAccess_GetTenantClusterMappingsByClusterIdClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder
+}
+
+// MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder is the mock recorder for MockClusterAccess_GetTenantClusterMappingsByClusterIdClient.
+type MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder struct {
+	mock *MockClusterAccess_GetTenantClusterMappingsByClusterIdClient
+}
+
+// NewMockClusterAccess_GetTenantClusterMappingsByClusterIdClient creates a new mock instance.
+func NewMockClusterAccess_GetTenantClusterMappingsByClusterIdClient(ctrl *gomock.Controller) *MockClusterAccess_GetTenantClusterMappingsByClusterIdClient {
+	mock := &MockClusterAccess_GetTenantClusterMappingsByClusterIdClient{ctrl: ctrl}
+	mock.recorder = &MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClusterAccess_GetTenantClusterMappingsByClusterIdClient) EXPECT() *MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByClusterIdClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByClusterIdClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByClusterIdClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByClusterIdClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByClusterIdClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByClusterIdClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByClusterIdClient) Recv() (*projections.TenantClusterBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*projections.TenantClusterBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByClusterIdClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByClusterIdClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByClusterIdClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByClusterIdClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByClusterIdClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockClusterAccess_GetTenantClusterMappingsByClusterIdClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockClusterAccess_GetTenantClusterMappingsByClusterIdClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockClusterAccess_GetTenantClusterMappingsByClusterIdClient)(nil).Trailer))
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/finleap-connect/monoskope/pkg/api/domain (interfaces: TenantClient,Tenant_GetAllClient)
+// Source: github.com/finleap-connect/monoskope/pkg/api/domain (interfaces: TenantClient,Tenant_GetAllClient,Tenant_GetUsersClient)
 
 // Package domain is a generated GoMock package.
 package domain
@@ -241,3 +241,126 @@ func (mr *MockTenant_GetAllClientMockRecorder) Trailer() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockTenant_GetAllClient)(nil).Trailer))
 }
+
+// MockTenant_GetUsersClient is a mock of Tenant_GetUsersClient interface.
+type MockTenant_GetUsersClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenant_GetUsersClientMockRecorder
+}
+
+// MockTenant_GetUsersClientMockRecorder is the mock recorder for MockTenant_GetUsersClient.
+type MockTenant_GetUsersClientMockRecorder struct {
+	mock *MockTenant_GetUsersClient
+}
+
+// NewMockTenant_GetUsersClient creates a new mock instance.
+func NewMockTenant_GetUsersClient(ctrl *gomock.Controller) *MockTenant_GetUsersClient {
+	mock := &MockTenant_GetUsersClient{ctrl: ctrl}
+	mock.recorder = &MockTenant_GetUsersClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenant_GetUsersClient) EXPECT() *MockTenant_GetUsersClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockTenant_GetUsersClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockTenant_GetUsersClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockTenant_GetUsersClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockTenant_GetUsersClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockTenant_GetUsersClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockTenant_GetUsersClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockTenant_GetUsersClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockTenant_GetUsersClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockTenant_GetUsersClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockTenant_GetUsersClient) Recv() (*projections.TenantUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*projections.TenantUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockTenant_GetUsersClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockTenant_GetUsersClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockTenant_GetUsersClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockTenant_GetUsersClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockTenant_GetUsersClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockTenant_GetUsersClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockTenant_GetUsersClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockTenant_GetUsersClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockTenant_GetUsersClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockTenant_GetUsersClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockTenant_GetUsersClient)(nil).Trailer))
+}